@@ -8,6 +8,7 @@
 package f9crew
 
 import (
+	"crypto/ed25519"
 	"crypto/sha256"
 	"fmt"
 )
@@ -16,7 +17,7 @@ import (
 // their name for display purposes, and a hash of their key to uniquely
 // identify them.
 type Interface interface {
-	// HashedKey returns a SHA256 hash of the crew's key.
+	// HashedKey returns a hash of the crew's key, used to uniquely identify them.
 	HashedKey() string
 
 	// Name resturns the crew member's name.
@@ -39,6 +40,8 @@ func HashKey(key string) string {
 type CrewMember struct {
 	name      string
 	hashedKey string
+	publicKey ed25519.PublicKey
+	profile   Profile
 }
 
 // NewCrewMember is a function to create a new crew member with the required
@@ -46,6 +49,17 @@ type CrewMember struct {
 // purposes. The second is their unique key, generated by the client. This is
 // simply used as an identifier for the crew member.
 func NewCrewMember(name string, key string) (*CrewMember, error) {
+	return NewCrewMemberWithHasher(name, key, SHA256Hasher)
+}
+
+// NewCrewMemberWithHasher is the same as NewCrewMember(), except that the
+// crew member's key is hashed using the Hasher provided. All crew members
+// within a deployment should be created using the same Hasher.
+func NewCrewMemberWithHasher(name string, key string, hasher Hasher) (*CrewMember, error) {
+	if hasher == nil {
+		return nil, ncmParamErr("hasher")
+	}
+
 	if name == "" {
 		return nil, ncmParamErr("name")
 	}
@@ -56,9 +70,30 @@ func NewCrewMember(name string, key string) (*CrewMember, error) {
 
 	cm := &CrewMember{
 		name:      name,
-		hashedKey: HashKey(key),
+		hashedKey: hasher.Hash(key),
+	}
+
+	return cm, nil
+}
+
+// NewCrewMemberFromPublicKey is a function to create a new crew member whose
+// identity is derived from their Ed25519 public key. The crew member's key is
+// the public key, so their HashedKey is the hash of it using the Hasher
+// provided. Crew created this way should only be trusted once they've proven
+// they hold the matching private key.
+func NewCrewMemberFromPublicKey(name string, pub ed25519.PublicKey, hasher Hasher) (*CrewMember, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("the crew member's public key must be %d bytes", ed25519.PublicKeySize)
 	}
 
+	cm, err := NewCrewMemberWithHasher(name, string(pub), hasher)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cm.publicKey = append(ed25519.PublicKey(nil), pub...)
+
 	return cm, nil
 }
 
@@ -66,9 +101,14 @@ func ncmParamErr(s string) error {
 	return fmt.Errorf("the crew member's %s cannot be an empty value", s)
 }
 
-// HashedKey returns a SHA256 hash of the crew's key. The user's key is not
-// persisted within the struct, only the resulting hash.
+// HashedKey returns a hash of the crew's key, which is SHA256 unless another
+// Hasher was used. The user's key is not persisted within the struct, only
+// the resulting hash.
 func (cm *CrewMember) HashedKey() string { return cm.hashedKey }
 
 // Name resturns the crew member's name.
 func (cm *CrewMember) Name() string { return cm.name }
+
+// PublicKey returns the crew member's Ed25519 public key. This is nil unless
+// the crew member was created using NewCrewMemberFromPublicKey().
+func (cm *CrewMember) PublicKey() ed25519.PublicKey { return cm.publicKey }
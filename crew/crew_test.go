@@ -5,6 +5,8 @@
 package f9crew_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"testing"
 
 	"github.com/theckman/falcon9/crew"
@@ -73,3 +75,34 @@ func (t *TestSuite) TestCrewMember_Name(c *C) {
 func (t *TestSuite) TestCrewMember_HashedKey(c *C) {
 	c.Check(t.crew.HashedKey(), Equals, "9bb5bde1a740465d012231e350aa8934f64d078ac1349d6a10852cbf1369d15f")
 }
+
+func (*TestSuite) TestNewCrewMemberFromPublicKey(c *C) {
+	var cm *f9crew.CrewMember
+	var err error
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromPublicKey("name", pub[:16], f9crew.SHA256Hasher)
+	c.Check(err, ErrorMatches, "the crew member's public key must be 32 bytes")
+	c.Check(cm, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromPublicKey("", pub, f9crew.SHA256Hasher)
+	c.Check(err, ErrorMatches, "the crew member's name cannot be an empty value")
+	c.Check(cm, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromPublicKey("name", pub, nil)
+	c.Check(err, ErrorMatches, "the crew member's hasher cannot be an empty value")
+	c.Check(cm, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromPublicKey("name", pub, f9crew.SHA256Hasher)
+	c.Assert(err, IsNil)
+	c.Assert(cm, NotNil)
+	c.Check(cm.Name(), Equals, "name")
+	c.Check(cm.HashedKey(), Equals, f9crew.HashKey(string(pub)))
+	c.Check(cm.PublicKey(), DeepEquals, pub)
+
+	cm, err = f9crew.NewCrewMember("name", "key")
+	c.Assert(err, IsNil)
+	c.Check(cm.PublicKey(), IsNil)
+}
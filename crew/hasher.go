@@ -0,0 +1,113 @@
+package f9crew
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// DefaultKDFIterations is the number of PBKDF2 iterations used by the Hasher
+// returned from NewKDFHasher(), if the number of iterations isn't specified.
+const DefaultKDFIterations = 100000
+
+// Hasher is the interface for turning a crew member's key in to the
+// identifier returned by HashedKey(). The same key must always result in the
+// same identifier, so that crew members can be identified when rejoining.
+type Hasher interface {
+	// Hash returns the string-representation of the hashed key.
+	Hash(key string) string
+}
+
+// HasherFunc is an adapter to allow the use of ordinary functions as a Hasher.
+type HasherFunc func(key string) string
+
+// Hash calls f(key).
+func (f HasherFunc) Hash(key string) string { return f(key) }
+
+// SHA256Hasher is the default Hasher. It's an unsalted SHA256 hash of the
+// key, which is the same as HashKey(). Because there is no salt, the same key
+// results in the same identifier across deployments.
+var SHA256Hasher Hasher = HasherFunc(HashKey)
+
+type hmacHasher struct {
+	secret []byte
+}
+
+// NewHMACHasher returns a Hasher which uses HMAC-SHA256, keyed with a secret
+// held by the server. Identifiers are stable as long as the secret stays the
+// same, but differ between deployments with different secrets.
+func NewHMACHasher(secret []byte) (Hasher, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("the HMAC secret cannot be empty")
+	}
+
+	return &hmacHasher{secret: append([]byte(nil), secret...)}, nil
+}
+
+func (h *hmacHasher) Hash(key string) string {
+	mac := hmac.New(sha256.New, h.secret)
+
+	mac.Write([]byte(key))
+
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
+
+type kdfHasher struct {
+	salt       []byte
+	iterations int
+}
+
+// NewKDFHasher returns a Hasher which uses PBKDF2-HMAC-SHA256. This is
+// deliberately slow, to make it expensive to brute-force the keys from their
+// identifiers. The salt should be unique to the deployment. If iterations is
+// zero, DefaultKDFIterations is used.
+func NewKDFHasher(salt []byte, iterations int) (Hasher, error) {
+	if len(salt) == 0 {
+		return nil, errors.New("the KDF salt cannot be empty")
+	}
+
+	if iterations < 0 {
+		return nil, errors.New("the number of KDF iterations cannot be negative")
+	}
+
+	if iterations == 0 {
+		iterations = DefaultKDFIterations
+	}
+
+	h := &kdfHasher{
+		salt:       append([]byte(nil), salt...),
+		iterations: iterations,
+	}
+
+	return h, nil
+}
+
+func (h *kdfHasher) Hash(key string) string {
+	return fmt.Sprintf("%x", pbkdf2SHA256([]byte(key), h.salt, h.iterations))
+}
+
+// pbkdf2SHA256 derives a sha256.Size key from the password using
+// PBKDF2-HMAC-SHA256 (RFC 8018). As the key is the same size as the hash, only
+// the first block needs to be computed.
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	mac := hmac.New(sha256.New, password)
+
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+
+	u := mac.Sum(nil)
+	dk := append([]byte(nil), u...)
+
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+
+		for j := range dk {
+			dk[j] ^= u[j]
+		}
+	}
+
+	return dk
+}
@@ -0,0 +1,72 @@
+package f9crew_test
+
+import (
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestSHA256Hasher(c *C) {
+	c.Check(f9crew.SHA256Hasher.Hash("test"), Equals, f9crew.HashKey("test"))
+}
+
+func (*TestSuite) TestNewHMACHasher(c *C) {
+	var h f9crew.Hasher
+	var err error
+
+	h, err = f9crew.NewHMACHasher(nil)
+	c.Check(err, ErrorMatches, "the HMAC secret cannot be empty")
+	c.Check(h, IsNil)
+
+	h, err = f9crew.NewHMACHasher([]byte("secret"))
+	c.Assert(err, IsNil)
+	c.Check(h.Hash("test"), Equals, "0329a06b62cd16b33eb6792be8c60b158d89a2ee3a876fce9a881ebb488c0914")
+
+	// a different secret results in a different identifier
+	h, err = f9crew.NewHMACHasher([]byte("another secret"))
+	c.Assert(err, IsNil)
+	c.Check(h.Hash("test"), Not(Equals), "0329a06b62cd16b33eb6792be8c60b158d89a2ee3a876fce9a881ebb488c0914")
+}
+
+func (*TestSuite) TestNewKDFHasher(c *C) {
+	var h f9crew.Hasher
+	var err error
+
+	h, err = f9crew.NewKDFHasher(nil, 0)
+	c.Check(err, ErrorMatches, "the KDF salt cannot be empty")
+	c.Check(h, IsNil)
+
+	h, err = f9crew.NewKDFHasher([]byte("salt"), -1)
+	c.Check(err, ErrorMatches, "the number of KDF iterations cannot be negative")
+	c.Check(h, IsNil)
+
+	h, err = f9crew.NewKDFHasher([]byte("salt"), 1000)
+	c.Assert(err, IsNil)
+	c.Check(h.Hash("test"), Equals, "b95de58f7646da3b2de64466b3429244885addac134dcab6e61a29ffe072404a")
+
+	// RFC 7914 test vector, truncated to the size of the hash
+	h, err = f9crew.NewKDFHasher([]byte("salt"), 1)
+	c.Assert(err, IsNil)
+	c.Check(h.Hash("passwd"), Equals, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc")
+}
+
+func (*TestSuite) TestNewCrewMemberWithHasher(c *C) {
+	var cm *f9crew.CrewMember
+	var err error
+
+	cm, err = f9crew.NewCrewMemberWithHasher("name", "key", nil)
+	c.Check(err, ErrorMatches, "the crew member's hasher cannot be an empty value")
+	c.Check(cm, IsNil)
+
+	h, err := f9crew.NewHMACHasher([]byte("secret"))
+	c.Assert(err, IsNil)
+
+	cm, err = f9crew.NewCrewMemberWithHasher("name", "test", h)
+	c.Assert(err, IsNil)
+	c.Assert(cm, NotNil)
+	c.Check(cm.HashedKey(), Equals, "0329a06b62cd16b33eb6792be8c60b158d89a2ee3a876fce9a881ebb488c0914")
+
+	// HashedKey() is stable for the same key and Hasher
+	cm2, err := f9crew.NewCrewMemberWithHasher("another name", "test", h)
+	c.Assert(err, IsNil)
+	c.Check(cm2.HashedKey(), Equals, cm.HashedKey())
+}
@@ -28,3 +28,15 @@ func (m Manifest) Less(i, j int) bool {
 
 	return name1 < name2
 }
+
+// Records returns the Record for each crew member in the manifest, in the
+// same order as the manifest.
+func (m Manifest) Records() []Record {
+	records := make([]Record, len(m))
+
+	for i, crew := range m {
+		records[i] = NewRecord(crew)
+	}
+
+	return records
+}
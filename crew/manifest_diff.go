@@ -0,0 +1,106 @@
+package f9crew
+
+// Rename is a crew member whose name changed between two manifests. The crew
+// member is identified by their HashedKey.
+type Rename struct {
+	HashedKey string
+	OldName   string
+	NewName   string
+}
+
+// ManifestDiff is the difference between two manifests. Crew members are
+// matched using their HashedKey.
+type ManifestDiff struct {
+	// Added is the crew only present in the new manifest.
+	Added Manifest
+
+	// Removed is the crew only present in the old manifest.
+	Removed Manifest
+
+	// Renamed is the crew present in both manifests, but with different names.
+	Renamed []Rename
+}
+
+// Empty returns whether there are no differences.
+func (d ManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0
+}
+
+func (m Manifest) index() map[string]Interface {
+	idx := make(map[string]Interface, len(m))
+
+	for _, crew := range m {
+		idx[crew.HashedKey()] = crew
+	}
+
+	return idx
+}
+
+// Diff returns the changes needed to go from this manifest to the other one.
+// The crew in the result are in the same order as they are in the manifests.
+func (m Manifest) Diff(other Manifest) ManifestDiff {
+	var diff ManifestDiff
+
+	oldIdx, newIdx := m.index(), other.index()
+
+	for _, crew := range m {
+		if _, ok := newIdx[crew.HashedKey()]; !ok {
+			diff.Removed = append(diff.Removed, crew)
+		}
+	}
+
+	for _, crew := range other {
+		old, ok := oldIdx[crew.HashedKey()]
+
+		if !ok {
+			diff.Added = append(diff.Added, crew)
+			continue
+		}
+
+		if old.Name() != crew.Name() {
+			diff.Renamed = append(diff.Renamed, Rename{
+				HashedKey: crew.HashedKey(),
+				OldName:   old.Name(),
+				NewName:   crew.Name(),
+			})
+		}
+	}
+
+	return diff
+}
+
+// Merge returns a new manifest with the crew of both manifests. If a crew
+// member is in both, the one from the other manifest is used. The crew from
+// this manifest come first, followed by the crew only in the other one.
+func (m Manifest) Merge(other Manifest) Manifest {
+	newIdx := other.index()
+	merged := make(Manifest, 0, len(m)+len(other))
+	seen := make(map[string]struct{}, len(m))
+
+	for _, crew := range m {
+		key := crew.HashedKey()
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		if newCrew, ok := newIdx[key]; ok {
+			crew = newCrew
+		}
+
+		merged = append(merged, crew)
+	}
+
+	for _, crew := range other {
+		if _, ok := seen[crew.HashedKey()]; ok {
+			continue
+		}
+
+		seen[crew.HashedKey()] = struct{}{}
+		merged = append(merged, crew)
+	}
+
+	return merged
+}
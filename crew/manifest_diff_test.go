@@ -0,0 +1,49 @@
+package f9crew_test
+
+import (
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestManifest_Diff(c *C) {
+	var diff f9crew.ManifestDiff
+
+	jeb, bill := &plainCrew{"Jebediah Kerman", "0"}, &plainCrew{"Bill Kerman", "1"}
+	bob, jeb2 := &plainCrew{"Bob Kerman", "2"}, &plainCrew{"Jeb", "0"}
+
+	old := f9crew.Manifest{jeb, bill}
+
+	diff = old.Diff(f9crew.Manifest{bill, jeb})
+	c.Check(diff.Empty(), Equals, true)
+
+	diff = old.Diff(f9crew.Manifest{jeb2, bob})
+	c.Check(diff.Empty(), Equals, false)
+	c.Check(diff.Added, DeepEquals, f9crew.Manifest{bob})
+	c.Check(diff.Removed, DeepEquals, f9crew.Manifest{bill})
+	c.Check(diff.Renamed, DeepEquals, []f9crew.Rename{{HashedKey: "0", OldName: "Jebediah Kerman", NewName: "Jeb"}})
+
+	diff = f9crew.Manifest(nil).Diff(old)
+	c.Check(diff.Added, DeepEquals, old)
+	c.Check(len(diff.Removed), Equals, 0)
+
+	diff = old.Diff(nil)
+	c.Check(diff.Removed, DeepEquals, old)
+	c.Check(len(diff.Added), Equals, 0)
+}
+
+func (*TestSuite) TestManifest_Merge(c *C) {
+	jeb, bill := &plainCrew{"Jebediah Kerman", "0"}, &plainCrew{"Bill Kerman", "1"}
+	bob, jeb2 := &plainCrew{"Bob Kerman", "2"}, &plainCrew{"Jeb", "0"}
+
+	old := f9crew.Manifest{jeb, bill}
+
+	merged := old.Merge(f9crew.Manifest{bob, jeb2})
+	c.Check(merged, DeepEquals, f9crew.Manifest{jeb2, bill, bob})
+
+	// the original manifest is unchanged
+	c.Check(old, DeepEquals, f9crew.Manifest{jeb, bill})
+
+	// duplicates are removed
+	merged = f9crew.Manifest{jeb, jeb}.Merge(f9crew.Manifest{bill, bill})
+	c.Check(merged, DeepEquals, f9crew.Manifest{jeb, bill})
+}
@@ -0,0 +1,283 @@
+package f9crew
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// MemberStatus is the status of a crew member within a mission.
+type MemberStatus struct {
+	// Vote is the crew member's current vote, as returned by the String()
+	// method of the mission's Vote type.
+	Vote string
+
+	// Reason is the reason the crew member gave for their vote, if any.
+	Reason string
+
+	// Weight is the weight of the crew member's vote.
+	Weight float64
+
+	// Decided is whether the crew member has cast a vote, other than
+	// abstaining or an unresolved conditional vote, in the current Go/No-Go.
+	Decided bool
+
+	// Go is whether the crew member's current vote is Go.
+	Go bool
+
+	// Present is whether the crew member is currently connected.
+	Present bool
+
+	// JoinedAt is when the crew member joined the mission.
+	JoinedAt time.Time
+
+	// Role is the crew member's role within the mission, if they have one.
+	Role string
+}
+
+// StatusMap is the status of the crew members in a mission. The key is the
+// crew member's HashedKey.
+type StatusMap map[string]MemberStatus
+
+// ManifestEntry is the representation of a crew member when encoding a
+// Manifest. The Vote, Reason, Weight and Present fields are only set if the
+// status of the crew member was provided.
+type ManifestEntry struct {
+	Record
+	Vote    string  `json:"vote,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+	Weight  float64 `json:"weight,omitempty"`
+	Present *bool   `json:"present,omitempty"`
+}
+
+var csvHeader = []string{"name", "hashed_key", "display_name", "avatar_url", "team", "timezone"}
+var csvStatusHeader = []string{"vote", "present"}
+
+// Entries returns the ManifestEntry for each crew member in the manifest, in
+// the same order as the manifest. The status may be nil.
+func (m Manifest) Entries(status StatusMap) []ManifestEntry {
+	entries := make([]ManifestEntry, len(m))
+
+	for i, crew := range m {
+		entries[i].Record = NewRecord(crew)
+
+		if s, ok := status[crew.HashedKey()]; ok {
+			present := s.Present
+			entries[i].Vote = s.Vote
+			entries[i].Reason = s.Reason
+			entries[i].Weight = s.Weight
+			entries[i].Present = &present
+		}
+	}
+
+	return entries
+}
+
+// WriteJSON writes the manifest to w as a JSON array of ManifestEntry. The
+// status may be nil.
+func (m Manifest) WriteJSON(w io.Writer, status StatusMap) error {
+	return json.NewEncoder(w).Encode(m.Entries(status))
+}
+
+// WriteCSV writes the manifest to w as CSV, with a header row. The profile
+// metadata is not included. If status is not nil, the vote and present
+// columns are included.
+func (m Manifest) WriteCSV(w io.Writer, status StatusMap) error {
+	cw := csv.NewWriter(w)
+
+	header := csvHeader
+
+	if status != nil {
+		header = append(header[:len(header):len(header)], csvStatusHeader...)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range m.Entries(status) {
+		p := e.Profile
+
+		if p == nil {
+			p = &Profile{}
+		}
+
+		row := []string{e.Name, e.HashedKey, p.DisplayName, p.AvatarURL, p.Team, p.Timezone}
+
+		if status != nil {
+			var present string
+
+			if e.Present != nil {
+				present = strconv.FormatBool(*e.Present)
+			}
+
+			row = append(row, e.Vote, present)
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteTable writes the manifest to w as a plain-text table, with the
+// columns aligned. If status is not nil, the vote and present columns are
+// included.
+func (m Manifest) WriteTable(w io.Writer, status StatusMap) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	if status == nil {
+		fmt.Fprintln(tw, "NAME\tTEAM\tHASHED KEY")
+	} else {
+		fmt.Fprintln(tw, "NAME\tTEAM\tVOTE\tPRESENT\tHASHED KEY")
+	}
+
+	for _, e := range m.Entries(status) {
+		name, team := e.Name, ""
+
+		if e.Profile != nil {
+			team = e.Profile.Team
+
+			if e.Profile.DisplayName != "" {
+				name = e.Profile.DisplayName
+			}
+		}
+
+		if status == nil {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, team, e.HashedKey)
+			continue
+		}
+
+		present := "-"
+
+		if e.Present != nil {
+			present = "no"
+
+			if *e.Present {
+				present = "yes"
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, team, e.Vote, present, e.HashedKey)
+	}
+
+	return tw.Flush()
+}
+
+// NewCrewMemberFromRecord is a function to create a crew member from an
+// existing Record, such as one from an exported manifest. The crew member's
+// key is not known, so the HashedKey is used as-is.
+func NewCrewMemberFromRecord(r Record) (*CrewMember, error) {
+	if r.Name == "" {
+		return nil, ncmParamErr("name")
+	}
+
+	if r.HashedKey == "" {
+		return nil, ncmParamErr("hashed key")
+	}
+
+	cm := &CrewMember{
+		name:      r.Name,
+		hashedKey: r.HashedKey,
+	}
+
+	if r.Profile != nil {
+		cm.profile = r.Profile.copy()
+	}
+
+	return cm, nil
+}
+
+// ParseJSON parses a manifest written by WriteJSON(). Any status in the
+// input is ignored.
+func ParseJSON(r io.Reader) (Manifest, error) {
+	var entries []ManifestEntry
+
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	m := make(Manifest, len(entries))
+
+	for i, e := range entries {
+		cm, err := NewCrewMemberFromRecord(e.Record)
+
+		if err != nil {
+			return nil, fmt.Errorf("manifest entry %d: %s", i, err)
+		}
+
+		m[i] = cm
+	}
+
+	return m, nil
+}
+
+// ParseCSV parses a manifest written by WriteCSV(). The header row is
+// required, and the columns may be in any order. Only the name and
+// hashed_key columns are required; any status columns are ignored.
+func ParseCSV(r io.Reader) (Manifest, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("the CSV manifest has no header row")
+	}
+
+	cols := make(map[string]int)
+
+	for i, name := range rows[0] {
+		cols[name] = i
+	}
+
+	for _, name := range csvHeader[:2] {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("the CSV manifest has no %s column", name)
+		}
+	}
+
+	m := make(Manifest, 0, len(rows)-1)
+
+	for i, row := range rows[1:] {
+		field := func(name string) string {
+			if idx, ok := cols[name]; ok {
+				return row[idx]
+			}
+			return ""
+		}
+
+		rec := Record{Name: field("name"), HashedKey: field("hashed_key")}
+
+		p := Profile{
+			DisplayName: field("display_name"),
+			AvatarURL:   field("avatar_url"),
+			Team:        field("team"),
+			Timezone:    field("timezone"),
+		}
+
+		if p.DisplayName != "" || p.AvatarURL != "" || p.Team != "" || p.Timezone != "" {
+			rec.Profile = &p
+		}
+
+		cm, err := NewCrewMemberFromRecord(rec)
+
+		if err != nil {
+			return nil, fmt.Errorf("manifest row %d: %s", i+1, err)
+		}
+
+		m = append(m, cm)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,175 @@
+package f9crew_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+func testManifest(c *C) f9crew.Manifest {
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+
+	jeb = jeb.WithProfile(f9crew.Profile{DisplayName: "Jeb", Team: "Pilots", Timezone: "UTC"})
+
+	return f9crew.Manifest{jeb, bill}
+}
+
+var testStatus = f9crew.StatusMap{
+	"5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9": {Vote: "Yes", Present: true},
+}
+
+func (*TestSuite) TestManifest_Entries(c *C) {
+	m := testManifest(c)
+
+	entries := m.Entries(f9crew.StatusMap{
+		m[0].HashedKey(): {Vote: "No", Reason: "fuel leak", Weight: 2, Present: true},
+	})
+	c.Assert(len(entries), Equals, 2)
+	c.Check(entries[0].Vote, Equals, "No")
+	c.Check(entries[0].Reason, Equals, "fuel leak")
+	c.Check(entries[0].Weight, Equals, 2.0)
+	c.Check(*entries[0].Present, Equals, true)
+	c.Check(entries[1].Reason, Equals, "")
+	c.Check(entries[1].Present, IsNil)
+}
+
+func (*TestSuite) TestManifest_WriteJSON(c *C) {
+	var buf bytes.Buffer
+
+	m := testManifest(c)
+
+	c.Assert(m.WriteJSON(&buf, nil), IsNil)
+	c.Check(buf.String(), Equals, `[{"name":"Jebediah Kerman",`+
+		`"hashed_key":"5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9",`+
+		`"profile":{"display_name":"Jeb","team":"Pilots","timezone":"UTC"}},`+
+		`{"name":"Bill Kerman",`+
+		`"hashed_key":"6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"}]`+"\n")
+
+	buf.Reset()
+
+	c.Assert(m.WriteJSON(&buf, testStatus), IsNil)
+	c.Check(strings.Contains(buf.String(), `"timezone":"UTC"},"vote":"Yes","present":true},`), Equals, true)
+
+	//
+	// Test that it can be parsed back
+	//
+	parsed, err := f9crew.ParseJSON(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(len(parsed), Equals, 2)
+	c.Check(parsed[0].Name(), Equals, "Jebediah Kerman")
+	c.Check(parsed[0].HashedKey(), Equals, m[0].HashedKey())
+	c.Check(f9crew.GetProfile(parsed[0]).Team, Equals, "Pilots")
+	c.Check(parsed[1].Name(), Equals, "Bill Kerman")
+	c.Check(parsed[1].HashedKey(), Equals, m[1].HashedKey())
+	c.Check(parsed.Records(), DeepEquals, m.Records())
+}
+
+func (*TestSuite) TestParseJSON(c *C) {
+	var err error
+
+	_, err = f9crew.ParseJSON(strings.NewReader(`{}`))
+	c.Check(err, NotNil)
+
+	_, err = f9crew.ParseJSON(strings.NewReader(`[{"name":"Jebediah Kerman"}]`))
+	c.Check(err, ErrorMatches, "manifest entry 0: the crew member's hashed key cannot be an empty value")
+}
+
+func (*TestSuite) TestManifest_WriteCSV(c *C) {
+	var buf bytes.Buffer
+
+	m := testManifest(c)
+
+	c.Assert(m.WriteCSV(&buf, nil), IsNil)
+	c.Check(buf.String(), Equals, "name,hashed_key,display_name,avatar_url,team,timezone\n"+
+		"Jebediah Kerman,5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9,Jeb,,Pilots,UTC\n"+
+		"Bill Kerman,6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b,,,,\n")
+
+	buf.Reset()
+
+	c.Assert(m.WriteCSV(&buf, testStatus), IsNil)
+	c.Check(buf.String(), Equals, "name,hashed_key,display_name,avatar_url,team,timezone,vote,present\n"+
+		"Jebediah Kerman,5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9,Jeb,,Pilots,UTC,Yes,true\n"+
+		"Bill Kerman,6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b,,,,,,\n")
+
+	//
+	// Test that it can be parsed back
+	//
+	parsed, err := f9crew.ParseCSV(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(len(parsed), Equals, 2)
+	c.Check(parsed[0].Name(), Equals, "Jebediah Kerman")
+	c.Check(parsed[0].HashedKey(), Equals, m[0].HashedKey())
+	c.Check(f9crew.GetProfile(parsed[0]), DeepEquals, f9crew.Profile{DisplayName: "Jeb", Team: "Pilots", Timezone: "UTC"})
+	c.Check(parsed[1].Name(), Equals, "Bill Kerman")
+	c.Check(parsed[1].HashedKey(), Equals, m[1].HashedKey())
+}
+
+func (*TestSuite) TestParseCSV(c *C) {
+	var m f9crew.Manifest
+	var err error
+
+	_, err = f9crew.ParseCSV(strings.NewReader(""))
+	c.Check(err, ErrorMatches, "the CSV manifest has no header row")
+
+	_, err = f9crew.ParseCSV(strings.NewReader("name,team\nJebediah Kerman,Pilots\n"))
+	c.Check(err, ErrorMatches, "the CSV manifest has no hashed_key column")
+
+	_, err = f9crew.ParseCSV(strings.NewReader("hashed_key,name\nabc,\n"))
+	c.Check(err, ErrorMatches, "manifest row 1: the crew member's name cannot be an empty value")
+
+	// columns may be in any order
+	m, err = f9crew.ParseCSV(strings.NewReader("hashed_key,name\nabc,Jebediah Kerman\n"))
+	c.Assert(err, IsNil)
+	c.Assert(len(m), Equals, 1)
+	c.Check(m[0].Name(), Equals, "Jebediah Kerman")
+	c.Check(m[0].HashedKey(), Equals, "abc")
+}
+
+func (*TestSuite) TestManifest_WriteTable(c *C) {
+	var buf bytes.Buffer
+
+	m := testManifest(c)
+
+	c.Assert(m.WriteTable(&buf, nil), IsNil)
+	c.Check(buf.String(), Equals, ""+
+		"NAME         TEAM    HASHED KEY\n"+
+		"Jeb          Pilots  5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9\n"+
+		"Bill Kerman          6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b\n")
+
+	buf.Reset()
+
+	c.Assert(m.WriteTable(&buf, testStatus), IsNil)
+	c.Check(buf.String(), Equals, ""+
+		"NAME         TEAM    VOTE  PRESENT  HASHED KEY\n"+
+		"Jeb          Pilots  Yes   yes      5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9\n"+
+		"Bill Kerman                -        6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b\n")
+}
+
+func (*TestSuite) TestNewCrewMemberFromRecord(c *C) {
+	var cm *f9crew.CrewMember
+	var err error
+
+	cm, err = f9crew.NewCrewMemberFromRecord(f9crew.Record{HashedKey: "abc"})
+	c.Check(err, ErrorMatches, "the crew member's name cannot be an empty value")
+	c.Check(cm, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromRecord(f9crew.Record{Name: "Jebediah Kerman"})
+	c.Check(err, ErrorMatches, "the crew member's hashed key cannot be an empty value")
+	c.Check(cm, IsNil)
+
+	cm, err = f9crew.NewCrewMemberFromRecord(f9crew.Record{
+		Name:      "Jebediah Kerman",
+		HashedKey: "abc",
+		Profile:   &f9crew.Profile{Team: "Pilots"},
+	})
+	c.Assert(err, IsNil)
+	c.Check(cm.Name(), Equals, "Jebediah Kerman")
+	c.Check(cm.HashedKey(), Equals, "abc")
+	c.Check(cm.Profile(), DeepEquals, f9crew.Profile{Team: "Pilots"})
+}
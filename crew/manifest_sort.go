@@ -0,0 +1,145 @@
+package f9crew
+
+import "sort"
+
+// LessFunc reports whether crew member a should sort before crew member b.
+type LessFunc func(a, b Interface) bool
+
+// Predicate reports whether a crew member should be kept by Filter().
+type Predicate func(crew Interface) bool
+
+type manifestSorter struct {
+	m    Manifest
+	less []LessFunc
+}
+
+func (s *manifestSorter) Len() int      { return len(s.m) }
+func (s *manifestSorter) Swap(i, j int) { s.m.Swap(i, j) }
+
+func (s *manifestSorter) Less(i, j int) bool {
+	a, b := s.m[i], s.m[j]
+
+	for _, less := range s.less {
+		switch {
+		case less(a, b):
+			return true
+		case less(b, a):
+			return false
+		}
+	}
+
+	// fall back to the default order
+	return s.m.Less(i, j)
+}
+
+// SortBy sorts the manifest using the LessFuncs provided. If two crew members
+// are equal according to the first LessFunc the next one is used, and so on.
+// If they're equal according to all of them, the manifest's Less() method is
+// used, so the order is always the same for the same crew.
+func (m Manifest) SortBy(less ...LessFunc) {
+	sort.Sort(&manifestSorter{m: m, less: less})
+}
+
+// Filter returns a new manifest with only the crew for which keep returns
+// true. The order of the crew is preserved.
+func (m Manifest) Filter(keep Predicate) Manifest {
+	var filtered Manifest
+
+	for _, crew := range m {
+		if keep(crew) {
+			filtered = append(filtered, crew)
+		}
+	}
+
+	return filtered
+}
+
+// ByName orders crew by their display name, from GetProfile().
+func ByName(a, b Interface) bool {
+	return GetProfile(a).DisplayName < GetProfile(b).DisplayName
+}
+
+// ByJoinTime orders crew by when they joined, earliest first. Crew without a
+// status sort last.
+func ByJoinTime(status StatusMap) LessFunc {
+	return func(a, b Interface) bool {
+		sa, aok := status[a.HashedKey()]
+		sb, bok := status[b.HashedKey()]
+
+		if !aok || !bok {
+			return aok && !bok
+		}
+
+		return sa.JoinedAt.Before(sb.JoinedAt)
+	}
+}
+
+// ByRole orders crew by their role. Crew without a role sort last.
+func ByRole(status StatusMap) LessFunc {
+	return func(a, b Interface) bool {
+		ra, rb := status[a.HashedKey()].Role, status[b.HashedKey()].Role
+
+		if ra == "" || rb == "" {
+			return ra != "" && rb == ""
+		}
+
+		return ra < rb
+	}
+}
+
+// voteRank is the order of crew when sorting ByVote.
+func voteRank(s MemberStatus) int {
+	switch {
+	case !s.Decided:
+		return 0
+	case !s.Go:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ByVote orders crew by their vote: undecided crew first, followed by those
+// who are No-Go, followed by those who are Go. This puts the crew that are
+// being waited on at the top.
+func ByVote(status StatusMap) LessFunc {
+	return func(a, b Interface) bool {
+		return voteRank(status[a.HashedKey()]) < voteRank(status[b.HashedKey()])
+	}
+}
+
+// ByPresence orders crew by whether they're present, with the crew who
+// aren't connected first.
+func ByPresence(status StatusMap) LessFunc {
+	return func(a, b Interface) bool {
+		return !status[a.HashedKey()].Present && status[b.HashedKey()].Present
+	}
+}
+
+// Undecided returns a Predicate keeping the crew who haven't voted yet.
+func Undecided(status StatusMap) Predicate {
+	return func(crew Interface) bool { return !status[crew.HashedKey()].Decided }
+}
+
+// Go returns a Predicate keeping the crew who have voted Go.
+func Go(status StatusMap) Predicate {
+	return func(crew Interface) bool { return status[crew.HashedKey()].Go }
+}
+
+// NoGo returns a Predicate keeping the crew who have voted, but not Go.
+func NoGo(status StatusMap) Predicate {
+	return func(crew Interface) bool {
+		s := status[crew.HashedKey()]
+		return s.Decided && !s.Go
+	}
+}
+
+// Present returns a Predicate keeping the crew who are connected.
+func Present(status StatusMap) Predicate {
+	return func(crew Interface) bool { return status[crew.HashedKey()].Present }
+}
+
+// HasRole returns a Predicate keeping the crew with the role provided.
+func HasRole(status StatusMap, role string) Predicate {
+	return func(crew Interface) bool { return status[crew.HashedKey()].Role == role }
+}
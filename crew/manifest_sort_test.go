@@ -0,0 +1,92 @@
+package f9crew_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+type sortFixture struct {
+	jeb, bill, bob, val f9crew.Interface
+	status              f9crew.StatusMap
+}
+
+func newSortFixture() *sortFixture {
+	now := time.Now()
+
+	return &sortFixture{
+		jeb:  &plainCrew{"Jebediah Kerman", "0"},
+		bill: &plainCrew{"Bill Kerman", "1"},
+		bob:  &plainCrew{"Bob Kerman", "2"},
+		val:  &plainCrew{"Valentina Kerman", "3"},
+		status: f9crew.StatusMap{
+			"0": {Vote: "Yes", Decided: true, Go: true, Present: true, JoinedAt: now.Add(time.Second * 3), Role: "Pilot"},
+			"1": {Vote: "No", Decided: true, Present: true, JoinedAt: now.Add(time.Second * 2), Role: "Engineer"},
+			"2": {Vote: "Abstain", Present: false, JoinedAt: now.Add(time.Second), Role: "Scientist"},
+			"3": {Vote: "Abstain", Present: true, JoinedAt: now, Role: "Pilot"},
+		},
+	}
+}
+
+func (f *sortFixture) manifest() f9crew.Manifest {
+	return f9crew.Manifest{f.jeb, f.bill, f.bob, f.val}
+}
+
+func (*TestSuite) TestManifest_SortBy(c *C) {
+	f := newSortFixture()
+	m := f.manifest()
+
+	m.SortBy(f9crew.ByName)
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bill, f.bob, f.jeb, f.val})
+
+	m.SortBy(f9crew.ByJoinTime(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.val, f.bob, f.bill, f.jeb})
+
+	// ties are broken by name
+	m.SortBy(f9crew.ByRole(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bill, f.jeb, f.val, f.bob})
+
+	m.SortBy(f9crew.ByVote(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bob, f.val, f.bill, f.jeb})
+
+	m.SortBy(f9crew.ByPresence(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bob, f.bill, f.jeb, f.val})
+
+	// multiple orders
+	m.SortBy(f9crew.ByRole(f.status), f9crew.ByJoinTime(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bill, f.val, f.jeb, f.bob})
+
+	// no orders falls back to Less()
+	m.SortBy()
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bill, f.bob, f.jeb, f.val})
+}
+
+func (*TestSuite) TestManifest_SortByMissingStatus(c *C) {
+	f := newSortFixture()
+	m := f.manifest()
+
+	delete(f.status, "3")
+	f.status["0"] = f9crew.MemberStatus{JoinedAt: f.status["0"].JoinedAt}
+
+	m.SortBy(f9crew.ByJoinTime(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bob, f.bill, f.jeb, f.val})
+
+	m.SortBy(f9crew.ByRole(f.status))
+	c.Check(m, DeepEquals, f9crew.Manifest{f.bill, f.bob, f.jeb, f.val})
+}
+
+func (*TestSuite) TestManifest_Filter(c *C) {
+	f := newSortFixture()
+	m := f.manifest()
+
+	c.Check(m.Filter(f9crew.Undecided(f.status)), DeepEquals, f9crew.Manifest{f.bob, f.val})
+	c.Check(m.Filter(f9crew.Go(f.status)), DeepEquals, f9crew.Manifest{f.jeb})
+	c.Check(m.Filter(f9crew.NoGo(f.status)), DeepEquals, f9crew.Manifest{f.bill})
+	c.Check(m.Filter(f9crew.Present(f.status)), DeepEquals, f9crew.Manifest{f.jeb, f.bill, f.val})
+	c.Check(m.Filter(f9crew.HasRole(f.status, "Pilot")), DeepEquals, f9crew.Manifest{f.jeb, f.val})
+	c.Check(len(m.Filter(f9crew.HasRole(f.status, "Janitor"))), Equals, 0)
+
+	// the original manifest is unchanged
+	c.Check(m, DeepEquals, f.manifest())
+}
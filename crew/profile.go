@@ -0,0 +1,112 @@
+package f9crew
+
+import "encoding/json"
+
+// Profile is the extended profile of a crew member. All of the fields are
+// optional.
+type Profile struct {
+	// DisplayName is the name to show for the crew member. If it's empty
+	// the crew member's Name() is shown instead.
+	DisplayName string `json:"display_name,omitempty"`
+
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Team      string `json:"team,omitempty"`
+
+	// Timezone is the crew member's IANA time zone, like "America/Chicago".
+	Timezone string `json:"timezone,omitempty"`
+
+	// Metadata is arbitrary key/value data about the crew member.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (p Profile) copy() Profile {
+	if p.Metadata != nil {
+		md := make(map[string]string, len(p.Metadata))
+
+		for k, v := range p.Metadata {
+			md[k] = v
+		}
+
+		p.Metadata = md
+	}
+
+	return p
+}
+
+func (p Profile) isZero() bool {
+	return p.DisplayName == "" && p.AvatarURL == "" && p.Team == "" && p.Timezone == "" && len(p.Metadata) == 0
+}
+
+// InterfaceProfile is an optional interface for crew members with an
+// extended profile. Implementations of Interface don't need to implement it,
+// use GetProfile() to obtain the profile of any crew member.
+type InterfaceProfile interface {
+	Interface
+
+	// Profile returns the crew member's extended profile.
+	Profile() Profile
+}
+
+// GetProfile returns the profile of the crew member. If the crew member
+// doesn't implement InterfaceProfile, or their profile has no DisplayName,
+// the DisplayName is set to their Name().
+func GetProfile(crew Interface) Profile {
+	var p Profile
+
+	if pc, ok := crew.(InterfaceProfile); ok {
+		p = pc.Profile()
+	}
+
+	if p.DisplayName == "" {
+		p.DisplayName = crew.Name()
+	}
+
+	return p
+}
+
+// Profile returns the crew member's extended profile.
+func (cm *CrewMember) Profile() Profile { return cm.profile.copy() }
+
+// WithProfile returns a copy of the crew member with the extended profile
+// set. The original crew member is not modified, so it's safe to use this on
+// a crew member that's already part of a mission.
+func (cm *CrewMember) WithProfile(p Profile) *CrewMember {
+	ncm := *cm
+	ncm.profile = p.copy()
+
+	return &ncm
+}
+
+// Record is the representation of a crew member used when encoding them, such
+// as in JSON API responses or messages to clients.
+type Record struct {
+	Name      string   `json:"name"`
+	HashedKey string   `json:"hashed_key"`
+	Profile   *Profile `json:"profile,omitempty"`
+}
+
+// NewRecord returns the Record for a crew member. The Profile is only set if
+// the crew member implements InterfaceProfile and their profile isn't empty.
+// Unlike GetProfile(), the DisplayName isn't filled in, so that the crew
+// member created from the Record by NewCrewMemberFromRecord() has the same
+// profile.
+func NewRecord(crew Interface) Record {
+	r := Record{
+		Name:      crew.Name(),
+		HashedKey: crew.HashedKey(),
+	}
+
+	if pc, ok := crew.(InterfaceProfile); ok {
+		if p := pc.Profile(); !p.isZero() {
+			r.Profile = &p
+		}
+	}
+
+	return r
+}
+
+// MarshalJSON satisfies the json.Marshaler interface. The crew member is
+// encoded as a Record.
+func (cm *CrewMember) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewRecord(cm))
+}
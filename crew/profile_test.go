@@ -0,0 +1,115 @@
+package f9crew_test
+
+import (
+	"encoding/json"
+
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+// plainCrew is an f9crew.Interface implementation without a profile.
+type plainCrew struct {
+	name, hashedKey string
+}
+
+func (p *plainCrew) Name() string      { return p.name }
+func (p *plainCrew) HashedKey() string { return p.hashedKey }
+
+func (*TestSuite) TestGetProfile(c *C) {
+	var p f9crew.Profile
+
+	//
+	// Test crew without a profile
+	//
+	p = f9crew.GetProfile(&plainCrew{name: "Jebediah Kerman", hashedKey: "abc"})
+	c.Check(p, DeepEquals, f9crew.Profile{DisplayName: "Jebediah Kerman"})
+
+	//
+	// Test crew with a profile
+	//
+	cm, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	p = f9crew.GetProfile(cm)
+	c.Check(p, DeepEquals, f9crew.Profile{DisplayName: "Jebediah Kerman"})
+
+	cm = cm.WithProfile(f9crew.Profile{DisplayName: "Jeb", Team: "Pilots"})
+
+	p = f9crew.GetProfile(cm)
+	c.Check(p, DeepEquals, f9crew.Profile{DisplayName: "Jeb", Team: "Pilots"})
+}
+
+func (*TestSuite) TestCrewMember_WithProfile(c *C) {
+	cm, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	md := map[string]string{"callsign": "Jeb"}
+
+	cm2 := cm.WithProfile(f9crew.Profile{
+		AvatarURL: "https://example.com/jeb.png",
+		Team:      "Pilots",
+		Timezone:  "UTC",
+		Metadata:  md,
+	})
+
+	// the original crew member is unchanged
+	c.Check(cm.Profile(), DeepEquals, f9crew.Profile{})
+
+	c.Check(cm2.Name(), Equals, cm.Name())
+	c.Check(cm2.HashedKey(), Equals, cm.HashedKey())
+	c.Check(cm2.Profile().Team, Equals, "Pilots")
+	c.Check(cm2.Profile().Timezone, Equals, "UTC")
+
+	// the metadata is copied
+	md["callsign"] = "Jebediah"
+	c.Check(cm2.Profile().Metadata["callsign"], Equals, "Jeb")
+
+	p := cm2.Profile()
+	p.Metadata["callsign"] = "Jebediah"
+	c.Check(cm2.Profile().Metadata["callsign"], Equals, "Jeb")
+}
+
+func (*TestSuite) TestCrewMember_MarshalJSON(c *C) {
+	cm, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	cm = cm.WithProfile(f9crew.Profile{Team: "Pilots", Metadata: map[string]string{"callsign": "Jeb"}})
+
+	b, err := json.Marshal(cm)
+	c.Assert(err, IsNil)
+	c.Check(string(b), Equals, `{"name":"Jebediah Kerman",`+
+		`"hashed_key":"5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9",`+
+		`"profile":{"team":"Pilots","metadata":{"callsign":"Jeb"}}}`)
+}
+
+func (*TestSuite) TestManifest_Records(c *C) {
+	cm, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	bob, err := f9crew.NewCrewMember("Bob Kerman", "2")
+	c.Assert(err, IsNil)
+
+	m := f9crew.Manifest{cm.WithProfile(f9crew.Profile{Team: "Pilots"}), &plainCrew{name: "Bill Kerman", hashedKey: "abc"}, bob}
+
+	records := m.Records()
+	c.Assert(len(records), Equals, 3)
+
+	c.Check(records[0].Name, Equals, "Jebediah Kerman")
+	c.Check(records[0].HashedKey, Equals, "5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9")
+	c.Assert(records[0].Profile, NotNil)
+	c.Check(*records[0].Profile, DeepEquals, f9crew.Profile{Team: "Pilots"})
+
+	c.Check(records[1], DeepEquals, f9crew.Record{Name: "Bill Kerman", HashedKey: "abc"})
+
+	// an empty profile is left out
+	c.Check(records[2].Profile, IsNil)
+
+	//
+	// Test that the records can be turned back into the same crew members
+	//
+	for i, r := range records {
+		cm, err := f9crew.NewCrewMemberFromRecord(r)
+		c.Assert(err, IsNil)
+		c.Check(f9crew.NewRecord(cm), DeepEquals, records[i])
+	}
+}
@@ -0,0 +1,90 @@
+package f9crew
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Roster is a named list of the crew expected to take part in a mission.
+// Unlike the crew of a mission, who are only known once they join, a roster
+// is prepared ahead of time and can be saved and loaded.
+type Roster struct {
+	Name string
+	Crew Manifest
+}
+
+type rosterJSON struct {
+	Name string          `json:"name"`
+	Crew []ManifestEntry `json:"crew"`
+}
+
+// Copy returns a copy of the roster, which doesn't share its crew. This
+// returns nil if the roster is nil.
+func (r *Roster) Copy() *Roster {
+	if r == nil {
+		return nil
+	}
+
+	return &Roster{
+		Name: r.Name,
+		Crew: append(Manifest(nil), r.Crew...),
+	}
+}
+
+// Contains returns whether the crew member is on the roster.
+func (r *Roster) Contains(hashedKey string) bool {
+	for _, crew := range r.Crew {
+		if crew.HashedKey() == hashedKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Keys returns the HashedKey of each crew member on the roster.
+func (r *Roster) Keys() []string {
+	keys := make([]string, len(r.Crew))
+
+	for i, crew := range r.Crew {
+		keys[i] = crew.HashedKey()
+	}
+
+	return keys
+}
+
+// Save writes the roster to w as JSON. It can be loaded using LoadRoster().
+func (r *Roster) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(rosterJSON{Name: r.Name, Crew: r.Crew.Entries(nil)})
+}
+
+// LoadRoster reads a roster written by Save().
+func LoadRoster(rd io.Reader) (*Roster, error) {
+	var rj rosterJSON
+
+	if err := json.NewDecoder(rd).Decode(&rj); err != nil {
+		return nil, err
+	}
+
+	if rj.Name == "" {
+		return nil, errors.New("the roster's name cannot be an empty value")
+	}
+
+	r := &Roster{
+		Name: rj.Name,
+		Crew: make(Manifest, len(rj.Crew)),
+	}
+
+	for i, e := range rj.Crew {
+		cm, err := NewCrewMemberFromRecord(e.Record)
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.Crew[i] = cm
+	}
+
+	return r, nil
+}
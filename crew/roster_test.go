@@ -0,0 +1,64 @@
+package f9crew_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/theckman/falcon9/crew"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestRoster_Contains(c *C) {
+	r := &f9crew.Roster{Name: "Pilots", Crew: testManifest(c)}
+
+	c.Check(r.Contains("5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"), Equals, true)
+	c.Check(r.Contains("6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"), Equals, true)
+	c.Check(r.Contains("d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35"), Equals, false)
+
+	c.Check(r.Keys(), DeepEquals, []string{
+		"5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9",
+		"6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b",
+	})
+}
+
+func (*TestSuite) TestRoster_Copy(c *C) {
+	var r *f9crew.Roster
+
+	c.Check(r.Copy(), IsNil)
+
+	r = &f9crew.Roster{Name: "Pilots", Crew: testManifest(c)}
+	cp := r.Copy()
+
+	c.Check(cp, DeepEquals, r)
+
+	cp.Crew[0] = cp.Crew[1]
+	c.Check(r.Contains("5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"), Equals, true)
+}
+
+func (*TestSuite) TestRoster_Save(c *C) {
+	var buf bytes.Buffer
+
+	r := &f9crew.Roster{Name: "Pilots", Crew: testManifest(c)}
+
+	c.Assert(r.Save(&buf), IsNil)
+
+	loaded, err := f9crew.LoadRoster(&buf)
+	c.Assert(err, IsNil)
+	c.Check(loaded.Name, Equals, "Pilots")
+	c.Check(loaded.Keys(), DeepEquals, r.Keys())
+	c.Check(loaded.Crew[0].Name(), Equals, "Jebediah Kerman")
+	c.Check(f9crew.GetProfile(loaded.Crew[0]).Team, Equals, "Pilots")
+}
+
+func (*TestSuite) TestLoadRoster(c *C) {
+	var err error
+
+	_, err = f9crew.LoadRoster(strings.NewReader(`[]`))
+	c.Check(err, NotNil)
+
+	_, err = f9crew.LoadRoster(strings.NewReader(`{"crew":[]}`))
+	c.Check(err, ErrorMatches, "the roster's name cannot be an empty value")
+
+	_, err = f9crew.LoadRoster(strings.NewReader(`{"name":"Pilots","crew":[{"name":"Jebediah Kerman"}]}`))
+	c.Check(err, ErrorMatches, "the crew member's hashed key cannot be an empty value")
+}
@@ -0,0 +1,59 @@
+package f9mission
+
+import "time"
+
+// Clock is the interface a mission uses to tell the time and to start
+// timers, such as the blastoff cooldown. It's set using the Clock mission
+// parameter, which allows tests to control the passing of time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer that sends the current time on its channel
+	// after at least the duration d.
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc waits for the duration d to elapse and then calls f. The
+	// returned Timer can be used to cancel the call using its Stop method,
+	// and its channel is not used.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is the interface for the timers returned by a Clock. It behaves the
+// same as a *time.Timer, except that the channel is returned by a method.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. It returns false if the timer
+	// has already expired or been stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after the duration d. It returns
+	// true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the Clock that uses the system time, via the time package.
+// It's used by missions that don't have the Clock mission parameter set.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{t: time.NewTimer(d)}
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return systemTimer{t: time.AfterFunc(d, f)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (st systemTimer) C() <-chan time.Time        { return st.t.C }
+func (st systemTimer) Stop() bool                 { return st.t.Stop() }
+func (st systemTimer) Reset(d time.Duration) bool { return st.t.Reset(d) }
@@ -0,0 +1,51 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestSystemClock(c *C) {
+	before := time.Now()
+	c.Check(f9mission.SystemClock.Now().Before(before), Equals, false)
+
+	t := f9mission.SystemClock.NewTimer(time.Millisecond)
+	c.Check((<-t.C()).Before(before), Equals, false)
+	c.Check(t.Stop(), Equals, false)
+
+	fired := make(chan struct{})
+	f9mission.SystemClock.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-fired
+}
+
+func (*TestSuite) TestMission_Clock(c *C) {
+	now := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := f9missiontest.NewClock(now)
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Clock: clock})
+	c.Assert(err, IsNil)
+
+	crew, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Minute)
+	c.Assert(m.AddCrew(crew, false), IsNil)
+	c.Check(m.CrewStatus()[crew.HashedKey()].JoinedAt, Equals, now.Add(time.Minute))
+
+	//
+	// Test that the blastoff cooldown uses the clock
+	//
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(crew.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(clock.Timers(), Equals, 1)
+
+	clock.Advance(time.Second * 10)
+	c.Check(m.CurrentState(), Equals, f9mission.StateFinished)
+}
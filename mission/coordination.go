@@ -0,0 +1,232 @@
+package f9mission
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotCoordinated is the error returned from LaunchAt() if the mission's
+// blastoff isn't being coordinated using Coordinate().
+var ErrNotCoordinated = errors.New("the mission's blastoff is not being coordinated")
+
+// InterfaceCoordination is the interface for coordinating the blastoff of a
+// mission with other missions, so that they can blast off together.
+type InterfaceCoordination interface {
+	// Coordinate holds the mission once it's ready for blastoff, until it's
+	// released by LaunchAt(). f is called each time the mission becomes
+	// ready or stops being ready, until the returned function is called.
+	// It's called synchronously, after the mission's locks have been
+	// released.
+	Coordinate(f func(ready bool)) (stop func())
+
+	// LaunchAt starts the blastoff at t, if the mission is still ready then.
+	// The zero time cancels the launch.
+	LaunchAt(t time.Time) error
+}
+
+type coordination struct {
+	f        *func(bool)
+	ready    bool
+	released bool
+	pending  []bool
+	mu       sync.Mutex
+}
+
+// Coordinate holds the mission once it's ready for blastoff, instead of
+// starting the blastoff, until it's released by LaunchAt(). This allows a
+// group of missions to blast off together.
+//
+// f is called each time the mission becomes ready or stops being ready,
+// which includes it aborting and its blastoff beginning, until the returned
+// function is called. It's called synchronously, after the mission's locks
+// have been released, so it may use the mission. If the mission is already
+// ready, f is called before Coordinate() returns.
+//
+// Calling Coordinate() again replaces f, and cancels the launch. Once the
+// mission is no longer coordinated, it blasts off as soon as it's ready.
+func (m *Mission) Coordinate(f func(ready bool)) (stop func()) {
+	// this is deferred first so that it runs after the mutexes are unlocked
+	defer m.flushStateChanges()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	key := &f
+
+	m.coord.mu.Lock()
+	m.coord.f, m.coord.ready, m.coord.released, m.coord.pending = key, false, false, nil
+	m.coord.mu.Unlock()
+
+	m.timerMu.Lock()
+	stopTimer(&m.launch)
+	m.timerMu.Unlock()
+
+	// let f know if the mission is already ready
+	if !m.closed() && m.CurrentState() == StateVoting {
+		m.proceed()
+	}
+
+	return func() {
+		// a closed mission can't blast off, so there's nothing to flush
+		if m.closed() {
+			m.uncoordinate(key)
+			return
+		}
+
+		defer m.flushStateChanges()
+
+		m.gngMu.Lock()
+		defer m.gngMu.Unlock()
+
+		m.crewMu.Lock()
+		defer m.crewMu.Unlock()
+
+		if m.uncoordinate(key) && m.CurrentState() == StateVoting {
+			m.proceed()
+		}
+	}
+}
+
+// uncoordinate stops coordinating the mission, if it's still being
+// coordinated by the Coordinate() call with the key, and returns whether it
+// was.
+func (m *Mission) uncoordinate(key *func(bool)) bool {
+	m.coord.mu.Lock()
+
+	if m.coord.f != key {
+		m.coord.mu.Unlock()
+		return false
+	}
+
+	m.coord.f, m.coord.ready, m.coord.released, m.coord.pending = nil, false, false, nil
+	m.coord.mu.Unlock()
+
+	m.timerMu.Lock()
+	stopTimer(&m.launch)
+	m.timerMu.Unlock()
+
+	return true
+}
+
+// LaunchAt releases the coordinated mission to start the blastoff at t, if
+// it's still ready then. If the launch window hasn't opened by t, it holds
+// until it does. Calling LaunchAt() again replaces the launch time, and the
+// zero time cancels the launch.
+//
+// If the mission isn't being coordinated using Coordinate(), this will return
+// a ErrNotCoordinated error.
+func (m *Mission) LaunchAt(t time.Time) error {
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	m.coord.mu.Lock()
+
+	coordinated := m.coord.f != nil
+	m.coord.released = false
+
+	m.coord.mu.Unlock()
+
+	if !coordinated {
+		return ErrNotCoordinated
+	}
+
+	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
+
+	stopTimer(&m.launch)
+
+	if t.IsZero() {
+		return nil
+	}
+
+	m.launch = m.clock.AfterFunc(t.Sub(m.clock.Now()), func() {
+		if m.closed() {
+			return
+		}
+
+		defer m.flushStateChanges()
+
+		m.gngMu.Lock()
+		defer m.gngMu.Unlock()
+
+		m.crewMu.Lock()
+		defer m.crewMu.Unlock()
+
+		m.timerMu.Lock()
+		m.launch = nil
+		m.timerMu.Unlock()
+
+		if m.CurrentState() == StateVoting && m.release() {
+			m.proceed()
+		}
+	})
+
+	return nil
+}
+
+// release releases the coordinated mission to start the blastoff, if it's
+// ready, and returns whether it was.
+func (m *Mission) release() bool {
+	m.coord.mu.Lock()
+	defer m.coord.mu.Unlock()
+
+	m.coord.released = m.coord.f != nil && m.coord.ready
+
+	return m.coord.released
+}
+
+// held returns whether the mission is being coordinated, and hasn't been
+// released to start the blastoff.
+func (m *Mission) held() bool {
+	m.coord.mu.Lock()
+	defer m.coord.mu.Unlock()
+
+	return m.coord.f != nil && !m.coord.released
+}
+
+// setReady records whether the coordinated mission is ready, and queues the
+// change to be sent to the Coordinate() function by flushStateChanges(). A
+// mission that stops being ready has to be released by LaunchAt() again. The
+// gngMu must be held by the caller.
+func (m *Mission) setReady(ready bool) {
+	m.coord.mu.Lock()
+	defer m.coord.mu.Unlock()
+
+	if !ready {
+		m.coord.released = false
+	}
+
+	if m.coord.f == nil || m.coord.ready == ready {
+		return
+	}
+
+	m.coord.ready = ready
+	m.coord.pending = append(m.coord.pending, ready)
+}
+
+// flushReadiness sends the queued readiness changes to the Coordinate()
+// function. The mission's locks must not be held by the caller.
+func (m *Mission) flushReadiness() {
+	for {
+		m.coord.mu.Lock()
+
+		if len(m.coord.pending) == 0 || m.coord.f == nil {
+			m.coord.pending = nil
+			m.coord.mu.Unlock()
+			return
+		}
+
+		ready := m.coord.pending[0]
+		m.coord.pending = m.coord.pending[1:]
+		f := *m.coord.f
+
+		m.coord.mu.Unlock()
+
+		f(ready)
+	}
+}
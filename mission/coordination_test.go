@@ -0,0 +1,163 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestMission_Coordinate(c *C) {
+	var ok bool
+	var err error
+
+	clock := f9missiontest.NewClock(windowEpoch)
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		BlastoffingCooldown: time.Second,
+		Clock:               clock,
+	})
+	c.Assert(err, IsNil)
+
+	keys := addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman")
+
+	c.Check(m.LaunchAt(windowEpoch), Equals, f9mission.ErrNotCoordinated)
+
+	var events []bool
+
+	stop := m.Coordinate(func(ready bool) { events = append(events, ready) })
+
+	//
+	// Test that a ready mission holds until it's released
+	//
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	ok, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(m.Holding(), Equals, true)
+	c.Check(events, DeepEquals, []bool{true})
+
+	ok, err = m.UpdateVote(keys[1], f9mission.VoteNo)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(m.Holding(), Equals, false)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(events, DeepEquals, []bool{true, false, true})
+
+	c.Assert(m.LaunchAt(clock.Now().Add(time.Second*5)), IsNil)
+
+	clock.Advance(time.Second * 4)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	clock.Advance(time.Second)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(events, DeepEquals, []bool{true, false, true, false})
+
+	clock.Advance(time.Second)
+	c.Check(m.CurrentState(), Equals, f9mission.StateFinished)
+
+	//
+	// Test that the launch can be cancelled
+	//
+	events = nil
+
+	c.Assert(m.Initiate(), IsNil)
+
+	for _, key := range keys {
+		_, err = m.UpdateVote(key, f9mission.VoteYes)
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(m.LaunchAt(clock.Now().Add(time.Second)), IsNil)
+	c.Assert(m.LaunchAt(time.Time{}), IsNil)
+
+	clock.Advance(time.Second * 2)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(m.Holding(), Equals, true)
+
+	//
+	// Test that a mission which stops being ready has to be released again
+	//
+	c.Assert(m.LaunchAt(clock.Now().Add(time.Second)), IsNil)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second * 2)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second * 2)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	//
+	// Test that aborting is a change in readiness
+	//
+	c.Assert(m.Abort(), IsNil)
+	c.Check(events, DeepEquals, []bool{true, false, true, false})
+
+	//
+	// Test that a mission blasts off when it's ready once it's no longer
+	// coordinated
+	//
+	c.Assert(m.Initiate(), IsNil)
+
+	for _, key := range keys {
+		_, err = m.UpdateVote(key, f9mission.VoteYes)
+		c.Assert(err, IsNil)
+	}
+
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	stop()
+
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(m.LaunchAt(clock.Now()), Equals, f9mission.ErrNotCoordinated)
+	c.Check(events, DeepEquals, []bool{true, false, true, false, true})
+}
+
+func (*TestSuite) TestMission_Coordinate_Window(c *C) {
+	var err error
+
+	m, clock, keys := newWindowMission(c)
+
+	clock.Advance(time.Minute * 30)
+	c.Assert(m.Initiate(), IsNil)
+
+	for _, key := range keys {
+		_, err = m.UpdateVote(key, f9mission.VoteYes)
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(m.Holding(), Equals, true)
+
+	//
+	// Test that a mission that's already ready tells f straight away
+	//
+	var events []bool
+
+	m.Coordinate(func(ready bool) { events = append(events, ready) })
+	c.Check(events, DeepEquals, []bool{true})
+
+	//
+	// Test that the mission doesn't blast off when its window opens, until
+	// it's released
+	//
+	clock.Advance(time.Minute * 30)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	c.Assert(m.LaunchAt(clock.Now().Add(-time.Minute)), IsNil)
+
+	clock.Advance(0)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+}
@@ -0,0 +1,185 @@
+package f9mission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/theckman/go-fsm"
+)
+
+// The text encodings below are also used by encoding/json, so a Vote or
+// GNGSetting is encoded as a JSON string and a Tally is encoded as a JSON
+// object keyed by the name of each vote (e.g., {"yes":2,"no":1}).
+
+// MarshalText implements encoding.TextMarshaler. The vote is encoded as the
+// lowercase version of its String() value.
+func (v Vote) MarshalText() ([]byte, error) {
+	switch v {
+	case VoteAbstain, VoteNo, VoteYes, VoteAbort, VoteConditional:
+		return []byte(strings.ToLower(v.String())), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal unknown vote %d", uint8(v))
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The vote name is not
+// case sensitive.
+func (v *Vote) UnmarshalText(text []byte) error {
+	vote, err := ParseVote(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*v = vote
+
+	return nil
+}
+
+// ParseVote returns the Vote with the given name. The name is not case
+// sensitive, so "yes" and "Yes" are both VoteYes.
+func ParseVote(s string) (Vote, error) {
+	switch strings.ToLower(s) {
+	case "abstain":
+		return VoteAbstain, nil
+	case "no":
+		return VoteNo, nil
+	case "yes":
+		return VoteYes, nil
+	case "abort":
+		return VoteAbort, nil
+	case "conditional":
+		return VoteConditional, nil
+	default:
+		return VoteAbstain, fmt.Errorf("unknown vote %q", s)
+	}
+}
+
+func (g GNGSetting) String() string {
+	switch g {
+	case GNGAll:
+		return "All"
+	case GNGQuorum:
+		return "Quorum"
+	case GNGWeightedQuorum:
+		return "WeightedQuorum"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler. The setting is encoded in
+// lowercase, with words separated by underscores (e.g., "weighted_quorum").
+func (g GNGSetting) MarshalText() ([]byte, error) {
+	switch g {
+	case GNGAll, GNGQuorum:
+		return []byte(strings.ToLower(g.String())), nil
+	case GNGWeightedQuorum:
+		return []byte("weighted_quorum"), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal unknown Go/No-Go setting %d", uint8(g))
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The setting name is not
+// case sensitive.
+func (g *GNGSetting) UnmarshalText(text []byte) error {
+	gng, err := ParseGNGSetting(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*g = gng
+
+	return nil
+}
+
+// ParseGNGSetting returns the GNGSetting with the given name. The name is not
+// case sensitive, so "quorum" and "Quorum" are both GNGQuorum.
+func ParseGNGSetting(s string) (GNGSetting, error) {
+	switch strings.ToLower(s) {
+	case "all":
+		return GNGAll, nil
+	case "quorum":
+		return GNGQuorum, nil
+	case "weighted_quorum":
+		return GNGWeightedQuorum, nil
+	default:
+		return GNGAll, fmt.Errorf("unknown Go/No-Go setting %q", s)
+	}
+}
+
+func (l VoteLock) String() string {
+	switch l {
+	case VoteLockNone:
+		return "None"
+	case VoteLockOnCast:
+		return "OnCast"
+	case VoteLockOnBlastoff:
+		return "OnBlastoff"
+	case VoteLockAbortOnRetract:
+		return "AbortOnRetract"
+	default:
+		return "Unknown"
+	}
+}
+
+var voteLockNames = map[VoteLock]string{
+	VoteLockNone:           "none",
+	VoteLockOnCast:         "on_cast",
+	VoteLockOnBlastoff:     "on_blastoff",
+	VoteLockAbortOnRetract: "abort_on_retract",
+}
+
+// MarshalText implements encoding.TextMarshaler. The setting is encoded in
+// lowercase, with words separated by underscores (e.g., "on_cast").
+func (l VoteLock) MarshalText() ([]byte, error) {
+	name, ok := voteLockNames[l]
+
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal unknown vote lock %d", uint8(l))
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The setting name is not
+// case sensitive.
+func (l *VoteLock) UnmarshalText(text []byte) error {
+	lock, err := ParseVoteLock(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*l = lock
+
+	return nil
+}
+
+// ParseVoteLock returns the VoteLock with the given name. The name is not
+// case sensitive, so "on_cast" and "On_Cast" are both VoteLockOnCast.
+func ParseVoteLock(s string) (VoteLock, error) {
+	lower := strings.ToLower(s)
+
+	for lock, name := range voteLockNames {
+		if name == lower {
+			return lock, nil
+		}
+	}
+
+	return VoteLockNone, fmt.Errorf("unknown vote lock %q", s)
+}
+
+// ParseState returns the mission state with the given name. The states are
+// already strings, so they encode as text without any help; this is for
+// validating states read from APIs and config files.
+func ParseState(s string) (fsm.State, error) {
+	switch state := fsm.State(strings.ToLower(s)); state {
+	case StateReady, StateVoting, StateBlastoffing, StateAborted, StateFinished:
+		return state, nil
+	default:
+		return "", fmt.Errorf("unknown mission state %q", s)
+	}
+}
@@ -0,0 +1,134 @@
+package f9mission_test
+
+import (
+	"encoding/json"
+
+	"github.com/theckman/falcon9/mission"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestVote_MarshalText(c *C) {
+	var text []byte
+	var err error
+
+	text, err = f9mission.VoteYes.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "yes")
+
+	text, err = f9mission.VoteAbstain.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "abstain")
+
+	_, err = f9mission.Vote(100).MarshalText()
+	c.Check(err, ErrorMatches, "cannot marshal unknown vote 100")
+}
+
+func (*TestSuite) TestVote_UnmarshalText(c *C) {
+	var v f9mission.Vote
+
+	c.Assert(v.UnmarshalText([]byte("No")), IsNil)
+	c.Check(v, Equals, f9mission.VoteNo)
+
+	c.Assert(v.UnmarshalText([]byte("ABORT")), IsNil)
+	c.Check(v, Equals, f9mission.VoteAbort)
+
+	c.Check(v.UnmarshalText([]byte("maybe")), ErrorMatches, `unknown vote "maybe"`)
+	c.Check(v, Equals, f9mission.VoteAbort)
+}
+
+func (*TestSuite) TestGNGSetting_String(c *C) {
+	c.Check(f9mission.GNGAll.String(), Equals, "All")
+	c.Check(f9mission.GNGQuorum.String(), Equals, "Quorum")
+	c.Check(f9mission.GNGWeightedQuorum.String(), Equals, "WeightedQuorum")
+	c.Check(f9mission.GNGSetting(100).String(), Equals, "Unknown")
+}
+
+func (*TestSuite) TestGNGSetting_Text(c *C) {
+	var g f9mission.GNGSetting
+
+	text, err := f9mission.GNGQuorum.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "quorum")
+
+	_, err = f9mission.GNGSetting(100).MarshalText()
+	c.Check(err, ErrorMatches, "cannot marshal unknown Go/No-Go setting 100")
+
+	c.Assert(g.UnmarshalText([]byte("Quorum")), IsNil)
+	c.Check(g, Equals, f9mission.GNGQuorum)
+
+	text, err = f9mission.GNGWeightedQuorum.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "weighted_quorum")
+
+	c.Assert(g.UnmarshalText(text), IsNil)
+	c.Check(g, Equals, f9mission.GNGWeightedQuorum)
+
+	c.Check(g.UnmarshalText([]byte("most")), ErrorMatches, `unknown Go/No-Go setting "most"`)
+}
+
+func (*TestSuite) TestVoteLock_Text(c *C) {
+	var l f9mission.VoteLock
+
+	c.Check(f9mission.VoteLockAbortOnRetract.String(), Equals, "AbortOnRetract")
+
+	text, err := f9mission.VoteLockOnBlastoff.MarshalText()
+	c.Assert(err, IsNil)
+	c.Check(string(text), Equals, "on_blastoff")
+
+	_, err = f9mission.VoteLock(100).MarshalText()
+	c.Check(err, ErrorMatches, "cannot marshal unknown vote lock 100")
+
+	c.Assert(l.UnmarshalText([]byte("On_Cast")), IsNil)
+	c.Check(l, Equals, f9mission.VoteLockOnCast)
+
+	c.Check(l.UnmarshalText([]byte("always")), ErrorMatches, `unknown vote lock "always"`)
+}
+
+func (*TestSuite) TestParseState(c *C) {
+	state, err := f9mission.ParseState("Blastoffing")
+	c.Assert(err, IsNil)
+	c.Check(state, Equals, f9mission.StateBlastoffing)
+
+	_, err = f9mission.ParseState("orbiting")
+	c.Check(err, ErrorMatches, `unknown mission state "orbiting"`)
+}
+
+func (*TestSuite) TestEncoding_JSON(c *C) {
+	var b []byte
+	var err error
+
+	//
+	// Test that the Tally is keyed by vote name
+	//
+	b, err = json.Marshal(f9mission.Tally{f9mission.VoteYes: 2, f9mission.VoteNo: 1})
+	c.Assert(err, IsNil)
+	c.Check(string(b), Equals, `{"no":1,"yes":2}`)
+
+	var tally f9mission.Tally
+	c.Assert(json.Unmarshal([]byte(`{"Yes":2,"abort":1}`), &tally), IsNil)
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 2, f9mission.VoteAbort: 1})
+
+	c.Check(json.Unmarshal([]byte(`{"1":2}`), &tally), NotNil)
+
+	//
+	// Test that votes and settings are strings within other values
+	//
+	config := struct {
+		GoNoGo  f9mission.GNGSetting `json:"go_no_go"`
+		Results f9mission.Results    `json:"results"`
+	}{
+		GoNoGo:  f9mission.GNGQuorum,
+		Results: f9mission.Results{"abc": f9mission.VoteYes},
+	}
+
+	b, err = json.Marshal(config)
+	c.Assert(err, IsNil)
+	c.Check(string(b), Equals, `{"go_no_go":"quorum","results":{"abc":"yes"}}`)
+
+	config.GoNoGo, config.Results = f9mission.GNGAll, nil
+	c.Assert(json.Unmarshal(b, &config), IsNil)
+	c.Check(config.GoNoGo, Equals, f9mission.GNGQuorum)
+	c.Check(config.Results, DeepEquals, f9mission.Results{"abc": f9mission.VoteYes})
+
+	c.Check(json.Unmarshal([]byte(`{"go_no_go":1}`), &config), NotNil)
+}
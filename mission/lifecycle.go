@@ -0,0 +1,134 @@
+package f9mission
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMissionClosed is the error returned when trying to change a mission
+// that has been closed, either by calling Close() or by the Context mission
+// parameter being cancelled.
+var ErrMissionClosed = errors.New("the mission has been closed")
+
+// InterfaceLifecycle is the interface for shutting down a mission. Once a
+// mission is closed its timers are stopped, and it no longer accepts crew or
+// votes.
+type InterfaceLifecycle interface {
+	// Close shuts down the mission, stopping any outstanding timers. It's
+	// safe to call more than once.
+	Close() error
+
+	// Done returns a channel that's closed once the mission is closed.
+	Done() <-chan struct{}
+}
+
+// setUpLifecycle creates the mission's context. If the parent context can
+// be cancelled, a goroutine is started to stop the mission's timers when it
+// is; the goroutine exits once the mission is closed.
+func (m *Mission) setUpLifecycle(parent context.Context) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	m.ctx, m.cancel = context.WithCancel(parent)
+
+	if parent.Done() != nil {
+		go func() {
+			<-m.ctx.Done()
+			m.stopTimers()
+			m.stopSessionTimers()
+		}()
+	}
+}
+
+// closed returns whether the mission has been closed.
+func (m *Mission) closed() bool {
+	return m.ctx != nil && m.ctx.Err() != nil
+}
+
+// startCooldown starts the blastoff cooldown timer, replacing any previous
+// one. The mission moves to StateFinished once the cooldown elapses, unless
+// it has been aborted or closed.
+func (m *Mission) startCooldown() {
+	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
+
+	stopTimer(&m.cooldown)
+
+	// the blastoff began within the launch window, so it can't be scrubbed
+	stopTimer(&m.scrub)
+	stopTimer(&m.holding)
+	stopTimer(&m.launch)
+
+	m.cooldown = m.clock.AfterFunc(m.blastoffCooldown, func() {
+		defer m.flushStateChanges()
+
+		if !m.closed() && m.CurrentState() == StateBlastoffing {
+			m.transition(StateFinished)
+		}
+	})
+}
+
+// stopTimer stops the timer, if there is one, and clears it. The timerMu
+// must be held by the caller.
+func stopTimer(t *Timer) {
+	if *t != nil {
+		(*t).Stop()
+		*t = nil
+	}
+}
+
+// stopTimers stops the blastoff cooldown, launch window, and coordinated
+// launch timers, if they're running.
+func (m *Mission) stopTimers() {
+	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
+
+	stopTimer(&m.cooldown)
+	stopTimer(&m.scrub)
+	stopTimer(&m.holding)
+	stopTimer(&m.launch)
+}
+
+// stopSessionTimers stops the expiry timers of the crew sessions that are
+// disconnected. It's separate from stopTimers(), as abort() calls that with
+// the crewMu held.
+func (m *Mission) stopSessionTimers() {
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	for _, s := range m.sessions {
+		stopTimer(&s.expiry)
+	}
+}
+
+// abort stops the mission's timers and aborts the mission.
+func (m *Mission) abort() error {
+	m.stopTimers()
+	m.setReady(false)
+	return m.transition(StateAborted)
+}
+
+// Close shuts down the mission, stopping any outstanding timers. Once the
+// mission is closed, adding crew, initiating a Go/No-Go, and voting all
+// return a ErrMissionClosed error. It's safe to call more than once.
+func (m *Mission) Close() error {
+	if m.cancel == nil {
+		return errUseNewMission
+	}
+
+	m.cancel()
+	m.stopTimers()
+	m.stopSessionTimers()
+
+	return nil
+}
+
+// Done returns a channel that's closed once the mission is closed.
+func (m *Mission) Done() <-chan struct{} {
+	if m.ctx == nil {
+		return nil
+	}
+
+	return m.ctx.Done()
+}
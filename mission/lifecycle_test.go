@@ -0,0 +1,133 @@
+package f9mission_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestMission_Close(c *C) {
+	var err error
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Clock: clock})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(clock.Timers(), Equals, 1)
+
+	c.Assert(m.Disconnect(jeb.HashedKey()), IsNil)
+	c.Check(clock.Timers(), Equals, 2)
+
+	//
+	// Test that closing the mission stops the cooldown and session expiry
+	//
+	c.Assert(m.Close(), IsNil)
+	c.Check(clock.Timers(), Equals, 0)
+	<-m.Done()
+
+	clock.Advance(time.Minute)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	// closing twice is fine
+	c.Check(m.Close(), IsNil)
+
+	//
+	// Test that a closed mission can't be changed
+	//
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+
+	c.Check(m.AddCrew(bill, false), Equals, f9mission.ErrMissionClosed)
+	c.Check(m.Initiate(), Equals, f9mission.ErrMissionClosed)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteAbort)
+	c.Check(err, Equals, f9mission.ErrMissionClosed)
+
+	token, err := m.SessionToken(jeb.HashedKey())
+	c.Assert(err, IsNil)
+	c.Check(m.Resume(token, jeb), Equals, f9mission.ErrMissionClosed)
+	c.Check(m.Disconnect(jeb.HashedKey()), Equals, f9mission.ErrMissionClosed)
+
+	c.Check((&f9mission.Mission{}).Close(), NotNil)
+}
+
+func (*TestSuite) TestMission_AbortStopsCooldown(c *C) {
+	var err error
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		Clock:               clock,
+		BlastoffingCooldown: time.Second * 10,
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+	c.Check(clock.Timers(), Equals, 0)
+
+	//
+	// Test that the old cooldown can't finish a new blastoff early
+	//
+	clock.Advance(time.Second * 5)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second * 5)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	clock.Advance(time.Second * 5)
+	c.Check(m.CurrentState(), Equals, f9mission.StateFinished)
+}
+
+func (*TestSuite) TestMission_Context(c *C) {
+	var err error
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Clock: clock, Context: ctx})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	cancel()
+	<-m.Done()
+
+	c.Check(m.Initiate(), Equals, f9mission.ErrMissionClosed)
+
+	// the timer is stopped asynchronously, but it can't finish the mission
+	clock.Advance(time.Minute)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+}
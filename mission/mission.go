@@ -1,6 +1,7 @@
 package f9mission
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -22,6 +23,12 @@ const (
 	// of crew members vote for blastoff. If there are too few crew members to
 	// reach quorum without all voting "Go", it falls back to GNGAll mode.
 	GNGQuorum
+
+	// GNGWeightedQuorum is the GoNoGo setting for requiring that more than
+	// half of the total weight of the crew votes for blastoff. Each crew
+	// member's vote counts using their weight from the Weights mission
+	// parameter.
+	GNGWeightedQuorum
 )
 
 const (
@@ -39,6 +46,8 @@ type Tally map[Vote]int
 // the crew member's HashedKey.
 type Results map[string]Vote
 
+var errWeightInvalid = errors.New("crew weights must be finite numbers that are not negative")
+
 var errUseNewMission = errors.New("use f9mission.NewMission() to create the *Mission struct")
 
 // ErrCrewMemberAlreadyPresent is the error returned from *Mission.AddUser
@@ -54,7 +63,8 @@ var ErrCrewMemberNotPresent = errors.New("the crew member you've tried to remove
 // without there being any crew members assigned to the mission.
 var ErrNoAssignedCrew = errors.New("before Initiating a Go/No-Go the mission must have crew assigned")
 
-// ErrMissionInProgress is the error returned from Initiate() if a mission is in progress.
+// ErrMissionInProgress is the error returned from Initiate(), Reset() and
+// Recycle() if a mission is in progress.
 var ErrMissionInProgress = errors.New("Go/No-Go vote is currently in progress")
 
 // ErrVotingNotInProgress is the error returned from UpdateVote() if a Go/No-Go is not in progress
@@ -84,6 +94,27 @@ type InterfaceManageCrew interface {
 	Crew() f9crew.Manifest
 }
 
+// InterfaceCrewStatus is the interface for getting the status of each crew
+// member of a mission.
+type InterfaceCrewStatus interface {
+	// CrewStatus returns the current vote, presence, join time, and role of
+	// each crew member, keyed by their HashedKey. The vote is only set while
+	// a Go/No-Go is in progress, or after one has ended.
+	CrewStatus() f9crew.StatusMap
+}
+
+// InterfaceRoster is the interface for missions with a roster of the crew
+// expected to take part.
+type InterfaceRoster interface {
+	// Roster returns the roster of crew expected to take part in the
+	// mission. This returns nil if the mission has no roster.
+	Roster() *f9crew.Roster
+
+	// Missing returns the crew members who are on the mission's roster, but
+	// who haven't been added to the mission.
+	Missing() f9crew.Manifest
+}
+
 // InterfaceLaunchControl is the interface to the launch control systems.
 // This includes Go/No-Go voting
 type InterfaceLaunchControl interface {
@@ -99,7 +130,9 @@ type InterfaceLaunchControl interface {
 	//
 	// If the mission is not initialized this will return a ErrVotingNotInProgress
 	// error. If the crew member is not assigned to this mission, this will return
-	// a ErrCrewMembeverNotPresent error.
+	// a ErrCrewMembeverNotPresent error. If the mission's VoteLock setting
+	// doesn't allow the vote to be changed, this will return a ErrVoteLocked
+	// error.
 	UpdateVote(hashedKey string, vote Vote) (bool, error)
 
 	// Tally returns the tally of votes and whether there are enough votes
@@ -107,6 +140,33 @@ type InterfaceLaunchControl interface {
 	Tally() (Tally, bool)
 }
 
+// InterfaceVoteReasons is the interface for crew giving reasons for their
+// votes.
+type InterfaceVoteReasons interface {
+	// UpdateVoteReason is the same as UpdateVote, except that the crew
+	// member can give a short reason for their vote. The reason replaces
+	// any reason given with their previous vote. If the reason is longer
+	// than MaxReasonLength this will return a ErrReasonTooLong error.
+	UpdateVoteReason(hashedKey string, vote Vote, reason string) (bool, error)
+
+	// Reasons returns the reasons given by crew members for their votes.
+	Reasons() Reasons
+}
+
+// InterfaceConditionalVotes is the interface for crew casting conditional
+// votes, which are resolved into a "Go" or "No" vote later.
+type InterfaceConditionalVotes interface {
+	// UpdateVoteConditional casts a VoteConditional for the crew member,
+	// which is a Go vote that's pending on the condition. The condition is
+	// used as the reason for the vote.
+	UpdateVoteConditional(hashedKey, condition string) (bool, error)
+
+	// ResolveConditional resolves the crew member's conditional vote into a
+	// VoteYes or VoteNo, keeping the condition as the reason. If their vote
+	// isn't conditional this will return a ErrNotConditional error.
+	ResolveConditional(hashedKey string, vote Vote) (bool, error)
+}
+
 // InterfaceAccessors is an interface type for accessor methods of the
 // mission parameters.
 type InterfaceAccessors interface {
@@ -127,6 +187,11 @@ type InterfaceAccessors interface {
 
 // Interface is the interface representing a falcon9 mission. This allows consumers
 // to write their own mission logic if they wish to do so.
+//
+// A *Mission also implements optional interfaces for its other features, such
+// as InterfaceSessions, which a consumer's own implementation doesn't need to
+// provide. Consumers should check for them using a type assertion on an
+// Interface value.
 type Interface interface {
 	InterfaceManageCrew
 	InterfaceAccessors
@@ -139,6 +204,64 @@ type MissionParams struct {
 	GoNoGo              GNGSetting
 	Name                string
 	BlastoffingCooldown time.Duration
+
+	// ResumeWindow is how long a disconnected crew member has to resume
+	// their session before their session token expires. If unset, this
+	// defaults to DefaultResumeWindow.
+	ResumeWindow time.Duration
+
+	// Roster is the crew expected to take part in the mission. If set, the
+	// expected crew who haven't joined count against the Go/No-Go: GNGAll
+	// requires every one of them to join and vote Go, and GNGQuorum counts
+	// them when working out the quorum. The mission keeps a copy of the
+	// roster, so modifying it once the mission is created has no effect.
+	Roster *f9crew.Roster
+
+	// OnCrewChange is called when the crew of the mission changes: when crew
+	// are added or removed, or when a crew member being replaced or resuming
+	// their session has a different name. It's called synchronously, after
+	// the mission's locks have been released.
+	OnCrewChange func(diff f9crew.ManifestDiff)
+
+	// Roles is the role of each crew member within the mission, keyed by
+	// their HashedKey. Crew don't need to have a role.
+	Roles map[string]string
+
+	// OnVote is called when a crew member casts a vote. It's called
+	// synchronously, after the mission's locks have been released.
+	OnVote func(event VoteEvent)
+
+	// Weights is the weight of each crew member's vote, keyed by their
+	// HashedKey, for use with GNGWeightedQuorum. Crew without a weight have
+	// the DefaultWeight. Weights cannot be negative.
+	Weights map[string]float64
+
+	// VoteLock is whether crew can change their votes once they're cast. The
+	// default is VoteLockNone, which allows votes to be changed freely.
+	VoteLock VoteLock
+
+	// Clock is used to tell the time, and to run the blastoff cooldown. If
+	// unset, this defaults to SystemClock.
+	Clock Clock
+
+	// Context is the parent context of the mission. If it's cancelled, the
+	// mission is closed in the same way as calling Close().
+	Context context.Context
+
+	// Stages is the launch sequence of the mission. If set, the Go/No-Go is
+	// run once for each stage, in order, and the GoNoGo parameter is not
+	// used. The blastoff begins once the last stage is ready.
+	Stages []Stage
+
+	// Window is the launch window of the mission. If set, the Go/No-Go can
+	// only be initiated once voting opens, the blastoff can only begin
+	// within the window, and the mission is scrubbed if the window closes
+	// before the blastoff begins.
+	Window *LaunchWindow
+
+	// OnStateChange is called when the state of the mission changes. It's
+	// called synchronously, after the mission's locks have been released.
+	OnStateChange func(change StateChange)
 }
 
 // Mission is the struct that implements the f9mission.Interface interface. This
@@ -148,13 +271,45 @@ type Mission struct {
 	name string
 	gng  GNGSetting
 
-	crew   map[string]f9crew.Interface
-	crewMu sync.Mutex
+	crew    map[string]f9crew.Interface
+	joined  map[string]time.Time
+	roles   map[string]string
+	weights map[string]float64
+	crewMu  sync.Mutex
+
+	sessions     map[string]*session
+	tokens       map[string]string
+	expired      map[string]string
+	resumeWindow time.Duration
+
+	roster       *f9crew.Roster
+	onCrewChange func(f9crew.ManifestDiff)
+	onVote       func(VoteEvent)
+
+	onStateChange func(StateChange)
+	state         stateWatchers
+	coord         coordination
 
 	stateMachine     *fsm.Machine
 	blastoffCooldown time.Duration
+	clock            Clock
+	cooldown         Timer
+	window           *LaunchWindow
+	scrub            Timer
+	holding          Timer
+	launch           Timer
+	timerMu          sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	stages       []Stage
+	stage        int
+	stageResults []StageResult
 
 	gngResults Results
+	gngReasons Reasons
+	voteLock   VoteLock
 	gngMu      sync.Mutex
 }
 
@@ -207,19 +362,70 @@ func NewMission(mp *MissionParams) (*Mission, error) {
 		mp.BlastoffingCooldown = time.Second * 10
 	}
 
+	if mp.ResumeWindow == 0 {
+		mp.ResumeWindow = DefaultResumeWindow
+	}
+
+	if mp.Clock == nil {
+		mp.Clock = SystemClock
+	}
+
+	if err := validateWeights(mp.Weights); err != nil {
+		return nil, err
+	}
+
+	if err := validateWindow(mp.Window); err != nil {
+		return nil, err
+	}
+
+	stages, err := copyStages(mp.Stages)
+
+	if err != nil {
+		return nil, err
+	}
+
 	m := &Mission{
 		id:               mp.ID,
 		name:             mp.Name,
 		gng:              mp.GoNoGo,
 		crew:             make(map[string]f9crew.Interface),
+		joined:           make(map[string]time.Time),
+		roles:            make(map[string]string, len(mp.Roles)),
+		weights:          make(map[string]float64, len(mp.Weights)),
 		stateMachine:     &fsm.Machine{},
 		blastoffCooldown: mp.BlastoffingCooldown,
+		sessions:         make(map[string]*session),
+		tokens:           make(map[string]string),
+		expired:          make(map[string]string),
+		resumeWindow:     mp.ResumeWindow,
+		roster:           mp.Roster.Copy(),
+		onCrewChange:     mp.OnCrewChange,
+		onVote:           mp.OnVote,
+		onStateChange:    mp.OnStateChange,
+		voteLock:         mp.VoteLock,
+		clock:            mp.Clock,
+		stages:           stages,
+	}
+
+	for hashedKey, role := range mp.Roles {
+		m.roles[hashedKey] = role
+	}
+
+	for hashedKey, weight := range mp.Weights {
+		m.weights[hashedKey] = weight
+	}
+
+	if mp.Window != nil {
+		w := *mp.Window
+		m.window = &w
 	}
 
 	if err := setUpStateMachine(m.stateMachine); err != nil {
 		return nil, err
 	}
 
+	m.setUpLifecycle(mp.Context)
+
 	return m, nil
 }
 
@@ -259,6 +465,76 @@ func (m *Mission) Crew() f9crew.Manifest {
 	return manifest
 }
 
+// Roster returns a copy of the roster of crew expected to take part in the
+// mission. This returns nil if the mission has no roster.
+func (m *Mission) Roster() *f9crew.Roster { return m.roster.Copy() }
+
+// Missing returns the crew members who are on the mission's roster, but who
+// haven't been added to the mission.
+func (m *Mission) Missing() f9crew.Manifest {
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	return m.missing()
+}
+
+// missing returns the roster crew who haven't been added to the mission.
+// The crewMu must be held by the caller.
+func (m *Mission) missing() f9crew.Manifest {
+	var manifest f9crew.Manifest
+
+	if m.roster == nil {
+		return manifest
+	}
+
+	for _, crew := range m.roster.Crew {
+		if _, ok := m.crew[crew.HashedKey()]; !ok {
+			manifest = append(manifest, crew)
+		}
+	}
+
+	return manifest
+}
+
+// CrewStatus returns the current vote, presence, join time, and role of each
+// crew member, keyed by their HashedKey. The vote is only set while a
+// Go/No-Go is in progress, or after one has ended. Crew members are present
+// unless their session is disconnected.
+func (m *Mission) CrewStatus() f9crew.StatusMap {
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	status := make(f9crew.StatusMap, len(m.crew))
+
+	for hashedKey := range m.crew {
+		s := f9crew.MemberStatus{
+			JoinedAt: m.joined[hashedKey],
+			Role:     m.roles[hashedKey],
+			Weight:   m.weight(hashedKey),
+		}
+
+		if m.gngResults != nil {
+			vote := m.gngResults[hashedKey]
+
+			s.Vote = vote.String()
+			s.Reason = m.gngReasons[hashedKey]
+			s.Decided = vote != VoteAbstain && vote != VoteConditional
+			s.Go = vote == VoteYes
+		}
+
+		if sess, ok := m.sessions[m.tokens[hashedKey]]; !ok || !sess.disconnected {
+			s.Present = true
+		}
+
+		status[hashedKey] = s
+	}
+
+	return status
+}
+
 // AddCrew is a function to add a new crew member to this mission. If the crew
 // member already exists (identified by their HashedKey), and replace is set to
 // false, this will return an f9crew.ErrCrewMemberAlreadyPresent error. However,
@@ -266,6 +542,10 @@ func (m *Mission) Crew() f9crew.Manifest {
 //
 // The latter is useful for a client quickly rejoining the session
 // after a network interruption. This assume clients have a unique key.
+// Replacing a crew member during a blastoff aborts it; to rejoin without
+// aborting, use Resume() with the crew member's session token.
+//
+// A new session token is issued to the crew member each time they are added.
 func (m *Mission) AddCrew(crew f9crew.Interface, replace bool) error {
 	// do some sanity checks before taking the mutex
 	// if the crew map is nil, this struct was improperly created
@@ -277,24 +557,48 @@ func (m *Mission) AddCrew(crew f9crew.Interface, replace bool) error {
 		return errors.New("a crew member cannot be nil")
 	}
 
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	var diff f9crew.ManifestDiff
+
+	// these are deferred first so that they run after the mutex is unlocked
+	defer m.flushStateChanges()
+	defer func() { m.crewChanged(diff) }()
+
 	m.crewMu.Lock()
 	defer m.crewMu.Unlock()
 
-	if _, ok := m.crew[crew.HashedKey()]; ok {
+	var previous f9crew.Manifest
+
+	if old, ok := m.crew[crew.HashedKey()]; ok {
 		// if we aren't going to replace the user
 		// return an error
 		if !replace {
 			return ErrCrewMemberAlreadyPresent
 		}
 
-		delete(m.crew, crew.HashedKey())
+		previous = f9crew.Manifest{old}
+	}
+
+	// this is done before the crew member is replaced, so that they're left
+	// in place if it fails
+	if err := m.issueSession(crew.HashedKey()); err != nil {
+		return err
 	}
 
 	m.crew[crew.HashedKey()] = crew
 
+	// a crew member being replaced keeps their original join time
+	if previous == nil {
+		m.joined[crew.HashedKey()] = m.clock.Now()
+	}
+
+	diff = previous.Diff(f9crew.Manifest{crew})
+
 	if m.CurrentState() == StateBlastoffing {
-		err := m.stateMachine.StateTransition(StateAborted)
-		return err
+		return m.abort()
 	}
 
 	return nil
@@ -304,11 +608,23 @@ func (m *Mission) AddCrew(crew f9crew.Interface, replace bool) error {
 // The crew member's HashedKey is used to do the lookup for determining which
 // crew member to remove from the mission. This returns the crew member being
 // removed, if a consumer wishes to use it.
+//
+// If a Go/No-Go is in progress, the votes of the remaining crew may now be
+// enough to proceed with blastoff, in which case it begins.
 func (m *Mission) RemoveCrew(hashedKey string) (f9crew.Interface, error) {
 	if hashedKey == "" {
 		return nil, errors.New("hashedKey parameter cannot be an empty string")
 	}
 
+	var diff f9crew.ManifestDiff
+
+	// these are deferred first so that they run after the mutex is unlocked
+	defer m.flushStateChanges()
+	defer func() { m.crewChanged(diff) }()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
 	m.crewMu.Lock()
 	defer m.crewMu.Unlock()
 
@@ -318,14 +634,51 @@ func (m *Mission) RemoveCrew(hashedKey string) (f9crew.Interface, error) {
 		return nil, ErrCrewMemberNotPresent
 	}
 
-	delete(m.crew, hashedKey)
+	diff.Removed = f9crew.Manifest{m.removeCrew(hashedKey)}
+
+	if m.CurrentState() == StateVoting {
+		if _, err := m.proceed(); err != nil {
+			return crew, err
+		}
+	}
 
 	return crew, nil
 }
 
+// removeCrew removes the crew member from the mission, along with their vote,
+// and returns them. If a Go/No-Go is in progress, the caller should call
+// proceed() afterwards, as the remaining crew may now be ready. The gngMu and
+// crewMu must be held by the caller.
+func (m *Mission) removeCrew(hashedKey string) f9crew.Interface {
+	crew := m.crew[hashedKey]
+
+	delete(m.crew, hashedKey)
+	delete(m.joined, hashedKey)
+	delete(m.gngResults, hashedKey)
+	delete(m.gngReasons, hashedKey)
+	m.dropSession(hashedKey)
+
+	return crew
+}
+
+// crewChanged calls the OnCrewChange mission parameter, if the diff isn't
+// empty. The crewMu must not be held by the caller.
+func (m *Mission) crewChanged(diff f9crew.ManifestDiff) {
+	if m.onCrewChange != nil && !diff.Empty() {
+		m.onCrewChange(diff)
+	}
+}
+
 // Initiate is the function that starts the Go/No-Go call. At this point people
 // can start adding votes to the mission.
 func (m *Mission) Initiate() error {
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	// this is deferred first so that it runs after the mutexes are unlocked
+	defer m.flushStateChanges()
+
 	m.crewMu.Lock()
 
 	n := len(m.crew)
@@ -342,15 +695,29 @@ func (m *Mission) Initiate() error {
 	switch m.CurrentState() {
 	case StateVoting, StateBlastoffing:
 		return ErrMissionInProgress
-	case StateAborted, StateFinished:
-		if err := m.stateMachine.StateTransition(StateReady); err != nil {
+	}
+
+	if err := m.checkWindow(); err != nil {
+		return err
+	}
+
+	if state := m.CurrentState(); state == StateAborted || state == StateFinished {
+		if err := m.transition(StateReady); err != nil {
 			return err
 		}
 	}
 
 	m.gngResults = make(Results)
+	m.gngReasons = make(Reasons)
+	m.resetStages()
+
+	if err := m.transition(StateVoting); err != nil {
+		return err
+	}
+
+	m.startScrubTimer()
 
-	return m.stateMachine.StateTransition(StateVoting)
+	return nil
 }
 
 // UpdateVote updates the vote of a crew member for the current mission.
@@ -359,54 +726,158 @@ func (m *Mission) Initiate() error {
 //
 // If the mission is not initialized this will return a ErrVotingNotInProgress
 // error. If the crew member is not assigned to this mission, this will return
-// a ErrCrewMembeverNotPresent error.
+// a ErrCrewMembeverNotPresent error. If the mission's VoteLock setting doesn't
+// allow the vote to be changed, this will return a ErrVoteLocked error.
 func (m *Mission) UpdateVote(hashedKey string, vote Vote) (bool, error) {
+	return m.UpdateVoteReason(hashedKey, vote, "")
+}
+
+// UpdateVoteReason is the same as UpdateVote, except that the crew member
+// can give a short reason for their vote. The reason replaces any reason
+// given with their previous vote, and is passed to the OnVote mission
+// parameter along with the vote.
+//
+// If the reason is longer than MaxReasonLength this will return a
+// ErrReasonTooLong error. A VoteConditional must have a reason, which is its
+// condition, or this will return a ErrConditionRequired error.
+func (m *Mission) UpdateVoteReason(hashedKey string, vote Vote, reason string) (bool, error) {
+	if err := validateReason(reason); err != nil {
+		return false, err
+	}
+
+	if vote == VoteConditional && reason == "" {
+		return false, ErrConditionRequired
+	}
+
+	var voted bool
+
+	// these are deferred first so that they run after the mutexes are unlocked
+	defer m.flushStateChanges()
+	defer func() {
+		if voted && m.onVote != nil {
+			m.onVote(VoteEvent{HashedKey: hashedKey, Vote: vote, Reason: reason})
+		}
+	}()
+
 	m.gngMu.Lock()
 	defer m.gngMu.Unlock()
 
-	switch m.CurrentState() {
-	case StateVoting, StateBlastoffing:
-		// pass without issue
-	default:
-		return false, ErrVotingNotInProgress
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	if err := m.canVote(hashedKey); err != nil {
+		return false, err
+	}
+
+	if err := m.checkVoteLock(hashedKey, vote); err != nil {
+		return false, err
+	}
+
+	if reason == "" {
+		delete(m.gngReasons, hashedKey)
+	} else {
+		m.gngReasons[hashedKey] = reason
+	}
+
+	voted = true
+
+	return m.castVote(hashedKey, vote)
+}
+
+// UpdateVoteConditional casts a VoteConditional for the crew member, which is
+// a Go vote that's pending on the condition (e.g., "once the weather
+// clears"). The condition is used as the reason for the vote, and the vote is
+// treated as not ready until it's resolved using ResolveConditional().
+func (m *Mission) UpdateVoteConditional(hashedKey, condition string) (bool, error) {
+	return m.UpdateVoteReason(hashedKey, VoteConditional, condition)
+}
+
+// ResolveConditional resolves the crew member's conditional vote into a
+// VoteYes or VoteNo, keeping the condition as the reason for the vote. The
+// bool value returned indicates whether there have been enough "Go" votes to
+// proceed with blastoff.
+//
+// If the crew member's current vote isn't a VoteConditional this will return
+// a ErrNotConditional error, and if the vote isn't VoteYes or VoteNo this will
+// return a ErrInvalidResolution error.
+func (m *Mission) ResolveConditional(hashedKey string, vote Vote) (bool, error) {
+	if vote != VoteYes && vote != VoteNo {
+		return false, ErrInvalidResolution
 	}
 
+	var event *VoteEvent
+
+	// these are deferred first so that they run after the mutexes are unlocked
+	defer m.flushStateChanges()
+	defer func() {
+		if event != nil && m.onVote != nil {
+			m.onVote(*event)
+		}
+	}()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
 	m.crewMu.Lock()
 	defer m.crewMu.Unlock()
 
-	if _, ok := m.crew[hashedKey]; !ok {
-		return false, ErrCrewMemberNotPresent
+	if err := m.canVote(hashedKey); err != nil {
+		return false, err
 	}
 
-	m.gngResults[hashedKey] = vote
+	if m.gngResults[hashedKey] != VoteConditional {
+		return false, ErrNotConditional
+	}
 
-	// if we are aborting...
-	if vote == VoteAbort {
-		err := m.stateMachine.StateTransition(StateAborted)
-		return false, err
+	event = &VoteEvent{
+		HashedKey: hashedKey,
+		Vote:      vote,
+		Reason:    m.gngReasons[hashedKey],
+		Resolved:  true,
+	}
+
+	return m.castVote(hashedKey, vote)
+}
+
+// canVote returns whether the crew member can currently vote. The gngMu and
+// crewMu must be held by the caller.
+func (m *Mission) canVote(hashedKey string) error {
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	switch m.CurrentState() {
+	case StateVoting, StateBlastoffing:
+		// pass without issue
+	default:
+		return ErrVotingNotInProgress
+	}
+
+	if _, ok := m.crew[hashedKey]; !ok {
+		return ErrCrewMemberNotPresent
 	}
 
-	isReady := m.isReady(m.tally())
+	if !m.inStage(hashedKey) {
+		return ErrNotInStage
+	}
 
-	// if this vote pushed us over the limit
-	if isReady && m.CurrentState() != StateBlastoffing {
-		err := m.stateMachine.StateTransition(StateBlastoffing)
+	return nil
+}
 
-		t := time.NewTimer(m.blastoffCooldown)
+// castVote records the crew member's vote, and starts or aborts the blastoff
+// as needed. The gngMu and crewMu must be held by the caller.
+func (m *Mission) castVote(hashedKey string, vote Vote) (bool, error) {
+	retracted := m.retracted(hashedKey, vote)
 
-		// spin off a goroutine to set our status back to StatusReady
-		// once the above timer fires
-		go func() {
-			<-t.C
-			if m.CurrentState() == StateBlastoffing {
-				m.stateMachine.StateTransition(StateFinished)
-			}
-		}()
+	m.gngResults[hashedKey] = vote
 
-		return isReady, err
+	// if we are aborting...
+	if vote == VoteAbort || retracted {
+		return false, m.abort()
 	}
 
-	return isReady, nil
+	// see if this vote pushed us over the limit
+	return m.proceed()
 }
 
 func (m *Mission) isReady(t Tally) bool {
@@ -415,14 +886,28 @@ func (m *Mission) isReady(t Tally) bool {
 		return false
 	}
 
-	numCrew := len(m.crew)
+	// unresolved conditional votes aren't counted as a "Go", so they hold
+	// the mission in the same way as a "No" until they're resolved
+
+	present, missing := m.electorate()
+	numCrew := len(present)
+
+	// with no crew left to vote, there's nothing to proceed on
+	if numCrew == 0 {
+		return false
+	}
+
+	// crew on the roster who haven't joined can't vote
+	numMissing := len(missing)
 
-	switch m.gng {
+	switch m.gngSetting() {
 	case GNGQuorum:
-		quroum := (numCrew / 2) + 1
+		quroum := ((numCrew + numMissing) / 2) + 1
 		return t[VoteYes] >= quroum
+	case GNGWeightedQuorum:
+		return m.weightedTally()[VoteYes] > m.totalWeight()/2
 	default:
-		return t[VoteYes] == numCrew
+		return numMissing == 0 && t[VoteYes] == numCrew
 	}
 }
 
@@ -436,6 +921,25 @@ func (m *Mission) tally() Tally {
 	return tally
 }
 
+// Reasons returns the reasons given by crew members for their votes in the
+// current Go/No-Go. This returns nil before Initiate() is called.
+func (m *Mission) Reasons() Reasons {
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	if m.gngReasons == nil {
+		return nil
+	}
+
+	reasons := make(Reasons, len(m.gngReasons))
+
+	for hashedKey, reason := range m.gngReasons {
+		reasons[hashedKey] = reason
+	}
+
+	return reasons
+}
+
 // Tally returns the tally of votes and whether there are enough votes
 // to proceed with the mission.
 func (m *Mission) Tally() (Tally, bool) {
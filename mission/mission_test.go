@@ -1,11 +1,14 @@
 package f9mission_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/theckman/falcon9/crew"
 	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
 	"github.com/theckman/go-fsm"
 
 	. "gopkg.in/check.v1"
@@ -20,17 +23,36 @@ var _ = Suite(&TestSuite{})
 func Test(t *testing.T) { TestingT(t) }
 
 func addCrew(m *f9mission.Mission, c *C) {
-	crew, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
-	c.Assert(err, IsNil)
-	c.Assert(m.AddCrew(crew, false), IsNil)
+	addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman", "Bob Kerman")
+}
 
-	crew, err = f9crew.NewCrewMember("Bill Kerman", "1")
-	c.Assert(err, IsNil)
-	c.Assert(m.AddCrew(crew, false), IsNil)
+// newCrew returns a crew member for each name, whose key is their index in
+// names, along with their HashedKeys.
+func newCrew(c *C, names ...string) (f9crew.Manifest, []string) {
+	crew := make(f9crew.Manifest, len(names))
+	keys := make([]string, len(names))
 
-	crew, err = f9crew.NewCrewMember("Bob Kerman", "2")
-	c.Assert(err, IsNil)
-	c.Assert(m.AddCrew(crew, false), IsNil)
+	for i, name := range names {
+		cm, err := f9crew.NewCrewMember(name, fmt.Sprint(i))
+		c.Assert(err, IsNil)
+
+		crew[i] = cm
+		keys[i] = cm.HashedKey()
+	}
+
+	return crew, keys
+}
+
+// addNamedCrew adds the crew from newCrew() to the mission, and returns their
+// HashedKeys.
+func addNamedCrew(m *f9mission.Mission, c *C, names ...string) []string {
+	crew, keys := newCrew(c, names...)
+
+	for _, cm := range crew {
+		c.Assert(m.AddCrew(cm, false), IsNil)
+	}
+
+	return keys
 }
 
 func (t *TestSuite) SetUpSuite(c *C) {
@@ -195,6 +217,54 @@ func (t *TestSuite) TestMission_RemoveCrew(c *C) {
 	c.Check(crew[1].HashedKey(), Equals, "5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9")
 }
 
+func (*TestSuite) TestMission_RemoveCrew_Voting(c *C) {
+	var ok bool
+	var err error
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Clock: clock})
+	c.Assert(err, IsNil)
+
+	addCrew(m, c)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote("5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9", f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	ok, err = m.UpdateVote("6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b", f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	//
+	// Test that removing the last crew member yet to vote starts the blastoff
+	//
+	_, err = m.RemoveCrew("d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35")
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	//
+	// Test that removing all of the crew doesn't start the blastoff
+	//
+	m, err = f9mission.NewMission(&f9mission.MissionParams{Clock: clock})
+	c.Assert(err, IsNil)
+
+	addCrew(m, c)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	for _, crew := range m.Crew() {
+		_, err = m.RemoveCrew(crew.HashedKey())
+		c.Assert(err, IsNil)
+	}
+
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	_, ok = m.Tally()
+	c.Check(ok, Equals, false)
+}
+
 func (*TestSuite) TestMission_Initiate(c *C) {
 	var m *f9mission.Mission
 	var err error
@@ -230,9 +300,12 @@ func (t *TestSuite) TestMission_UpdateVote(c *C) {
 	var err error
 	var state fsm.State
 
+	clock := f9missiontest.NewClock(time.Now())
+
 	m, err := f9mission.NewMission(&f9mission.MissionParams{
 		GoNoGo:              f9mission.GNGQuorum,
 		BlastoffingCooldown: time.Millisecond * 100,
+		Clock:               clock,
 	})
 	c.Check(err, IsNil)
 	c.Check(m, NotNil)
@@ -377,8 +450,11 @@ func (t *TestSuite) TestMission_UpdateVote(c *C) {
 	//
 	// Test that state transitions to StateFinished after the BlastoffingCooldown timer
 	//
+	clock.Advance(time.Millisecond * 99)
+	state = m.CurrentState()
+	c.Check(state, Equals, f9mission.StateBlastoffing)
 
-	time.Sleep(time.Millisecond * 110)
+	clock.Advance(time.Millisecond)
 	state = m.CurrentState()
 	c.Check(state, Equals, f9mission.StateFinished)
 }
@@ -474,3 +550,389 @@ func (t *TestSuite) TestMission_Tally(c *C) {
 	c.Check(tally[f9mission.VoteAbstain], Equals, 0)
 	c.Check(tally[f9mission.VoteAbort], Equals, 1)
 }
+
+func (*TestSuite) TestMission_CrewStatus(c *C) {
+	var status f9crew.StatusMap
+	var s f9crew.MemberStatus
+
+	before := time.Now()
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		Roles: map[string]string{"5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9": "Pilot"},
+	})
+	c.Assert(err, IsNil)
+
+	addCrew(m, c)
+
+	//
+	// Test that there are no votes before Initiate() is called
+	//
+	status = m.CrewStatus()
+	c.Assert(len(status), Equals, 3)
+
+	s = status["5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"]
+	c.Check(s.Vote, Equals, "")
+	c.Check(s.Decided, Equals, false)
+	c.Check(s.Present, Equals, true)
+	c.Check(s.Role, Equals, "Pilot")
+	c.Check(s.JoinedAt.Before(before), Equals, false)
+
+	c.Check(status["6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"].Role, Equals, "")
+
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote("5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9", f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote("d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35", f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	c.Assert(m.Disconnect("6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"), IsNil)
+
+	status = m.CrewStatus()
+	c.Assert(len(status), Equals, 3)
+
+	s = status["5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"]
+	c.Check(s.Vote, Equals, "Yes")
+	c.Check(s.Decided, Equals, true)
+	c.Check(s.Go, Equals, true)
+	c.Check(s.Present, Equals, true)
+
+	s = status["6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"]
+	c.Check(s.Vote, Equals, "Abstain")
+	c.Check(s.Decided, Equals, false)
+	c.Check(s.Go, Equals, false)
+	c.Check(s.Present, Equals, false)
+
+	s = status["d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35"]
+	c.Check(s.Vote, Equals, "No")
+	c.Check(s.Decided, Equals, true)
+	c.Check(s.Go, Equals, false)
+	c.Check(s.Present, Equals, true)
+
+	//
+	// Test that replacing a crew member keeps their join time
+	//
+	joined := status["d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35"].JoinedAt
+
+	crew, err := f9crew.NewCrewMember("Bob Kerman", "2")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(crew, true), IsNil)
+
+	c.Check(m.CrewStatus()["d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35"].JoinedAt, Equals, joined)
+}
+
+func (*TestSuite) TestMission_Roster(c *C) {
+	var ok bool
+	var err error
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+
+	roster := &f9crew.Roster{Name: "Pilots", Crew: f9crew.Manifest{jeb, bill}}
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Roster: roster})
+	c.Assert(err, IsNil)
+	c.Check(m.Roster(), DeepEquals, roster)
+	c.Check(m.Roster(), Not(Equals), roster)
+
+	//
+	// Test that the mission isn't affected by changes to the roster
+	//
+	roster.Name = "Engineers"
+	roster.Crew[1] = jeb
+
+	c.Check(m.Roster().Name, Equals, "Pilots")
+
+	m.Roster().Crew[1] = jeb
+	c.Check(m.Roster().Crew[1].HashedKey(), Equals, bill.HashedKey())
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+
+	//
+	// Test that the crew who haven't joined are missing
+	//
+	missing := m.Missing()
+	c.Assert(len(missing), Equals, 1)
+	c.Check(missing[0].HashedKey(), Equals, bill.HashedKey())
+
+	//
+	// Test that GNGAll requires the whole roster
+	//
+	c.Assert(m.Initiate(), IsNil)
+
+	ok, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	c.Assert(m.AddCrew(bill, false), IsNil)
+	c.Check(len(m.Missing()), Equals, 0)
+
+	ok, err = m.UpdateVote(bill.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	//
+	// Test that missions without a roster have no missing crew
+	//
+	m, err = f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+	c.Check(m.Roster(), IsNil)
+	c.Check(len(m.Missing()), Equals, 0)
+}
+
+func (*TestSuite) TestMission_RosterQuorum(c *C) {
+	var ok bool
+	var err error
+
+	roster := &f9crew.Roster{Name: "Kerbals"}
+
+	for i := 0; i < 4; i++ {
+		crew, err := f9crew.NewCrewMember(fmt.Sprintf("Kerbal %d", i), fmt.Sprint(i))
+		c.Assert(err, IsNil)
+
+		roster.Crew = append(roster.Crew, crew)
+	}
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo: f9mission.GNGQuorum,
+		Roster: roster,
+	})
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(roster.Crew[0], false), IsNil)
+	c.Assert(m.AddCrew(roster.Crew[1], false), IsNil)
+	c.Assert(m.Initiate(), IsNil)
+
+	//
+	// Test that the quorum of the four rostered crew is three, even though
+	// only two have joined
+	//
+	_, err = m.UpdateVote(roster.Crew[0].HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	ok, err = m.UpdateVote(roster.Crew[1].HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	c.Assert(m.AddCrew(roster.Crew[2], false), IsNil)
+
+	ok, err = m.UpdateVote(roster.Crew[2].HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+}
+
+func (*TestSuite) TestMission_OnCrewChange(c *C) {
+	var diffs []f9crew.ManifestDiff
+	var m *f9mission.Mission
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		OnCrewChange: func(diff f9crew.ManifestDiff) {
+			// this would deadlock if the mission were still locked
+			m.Crew()
+			diffs = append(diffs, diff)
+		},
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(len(diffs), Equals, 1)
+	c.Check(diffs[0].Added, DeepEquals, f9crew.Manifest{jeb})
+
+	// errors, and replacing with the same name, don't change the crew
+	c.Check(m.AddCrew(jeb, false), Equals, f9mission.ErrCrewMemberAlreadyPresent)
+	c.Assert(m.AddCrew(jeb, true), IsNil)
+	c.Assert(len(diffs), Equals, 1)
+
+	jeb2, err := f9crew.NewCrewMember("Jeb", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb2, true), IsNil)
+	c.Assert(len(diffs), Equals, 2)
+	c.Check(diffs[1].Renamed, DeepEquals, []f9crew.Rename{{HashedKey: jeb.HashedKey(), OldName: "Jebediah Kerman", NewName: "Jeb"}})
+
+	token, err := m.SessionToken(jeb.HashedKey())
+	c.Assert(err, IsNil)
+
+	c.Assert(m.Resume(token, jeb), IsNil)
+	c.Assert(len(diffs), Equals, 3)
+	c.Check(diffs[2].Renamed, DeepEquals, []f9crew.Rename{{HashedKey: jeb.HashedKey(), OldName: "Jeb", NewName: "Jebediah Kerman"}})
+
+	_, err = m.RemoveCrew(jeb.HashedKey())
+	c.Assert(err, IsNil)
+	c.Assert(len(diffs), Equals, 4)
+	c.Check(diffs[3].Removed, DeepEquals, f9crew.Manifest{jeb})
+}
+
+func (*TestSuite) TestMission_UpdateVoteReason(c *C) {
+	var events []f9mission.VoteEvent
+	var m *f9mission.Mission
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo: f9mission.GNGAll,
+		OnVote: func(event f9mission.VoteEvent) {
+			// this would deadlock if the mission were still locked
+			m.Reasons()
+			events = append(events, event)
+		},
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(m.AddCrew(bill, false), IsNil)
+
+	c.Check(m.Reasons(), IsNil)
+
+	//
+	// Test that votes can't be cast before the Go/No-Go
+	//
+	_, err = m.UpdateVoteReason(jeb.HashedKey(), f9mission.VoteNo, "fuel leak")
+	c.Check(err, NotNil)
+	c.Check(len(events), Equals, 0)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	//
+	// Test that reasons are stored and broadcast
+	//
+	ready, err := m.UpdateVoteReason(jeb.HashedKey(), f9mission.VoteNo, "fuel leak")
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+	c.Check(m.Reasons(), DeepEquals, f9mission.Reasons{jeb.HashedKey(): "fuel leak"})
+	c.Assert(len(events), Equals, 1)
+	c.Check(events[0], Equals, f9mission.VoteEvent{HashedKey: jeb.HashedKey(), Vote: f9mission.VoteNo, Reason: "fuel leak"})
+
+	status := m.CrewStatus()
+	c.Check(status[jeb.HashedKey()].Reason, Equals, "fuel leak")
+	c.Check(status[bill.HashedKey()].Reason, Equals, "")
+
+	//
+	// Test that a reason that's too long is rejected without changing the vote
+	//
+	_, err = m.UpdateVoteReason(jeb.HashedKey(), f9mission.VoteYes, strings.Repeat("x", f9mission.MaxReasonLength+1))
+	c.Check(err, Equals, f9mission.ErrReasonTooLong)
+	c.Check(len(events), Equals, 1)
+
+	tally, _ := m.Tally()
+	c.Check(tally[f9mission.VoteNo], Equals, 1)
+
+	//
+	// Test that voting without a reason clears the previous one
+	//
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.Reasons(), DeepEquals, f9mission.Reasons{})
+	c.Assert(len(events), Equals, 2)
+	c.Check(events[1], Equals, f9mission.VoteEvent{HashedKey: jeb.HashedKey(), Vote: f9mission.VoteYes})
+
+	//
+	// Test that reasons are reset by a new Go/No-Go
+	//
+	_, err = m.UpdateVoteReason(bill.HashedKey(), f9mission.VoteNo, "weather")
+	c.Assert(err, IsNil)
+	c.Check(len(m.Reasons()), Equals, 1)
+
+	_, err = m.UpdateVote(bill.HashedKey(), f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+
+	c.Assert(m.Initiate(), IsNil)
+	c.Check(m.Reasons(), DeepEquals, f9mission.Reasons{})
+}
+
+func (*TestSuite) TestMission_ConditionalVote(c *C) {
+	var events []f9mission.VoteEvent
+	var ready bool
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGAll,
+		BlastoffingCooldown: time.Millisecond * 10,
+		OnVote: func(event f9mission.VoteEvent) {
+			events = append(events, event)
+		},
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Assert(m.AddCrew(bill, false), IsNil)
+	c.Assert(m.Initiate(), IsNil)
+
+	//
+	// Test that a conditional vote needs a condition
+	//
+	_, err = m.UpdateVoteConditional(jeb.HashedKey(), "")
+	c.Check(err, Equals, f9mission.ErrConditionRequired)
+
+	_, err = m.UpdateVoteReason(jeb.HashedKey(), f9mission.VoteConditional, "")
+	c.Check(err, Equals, f9mission.ErrConditionRequired)
+
+	//
+	// Test that only conditional votes can be resolved, and only to Yes or No
+	//
+	_, err = m.ResolveConditional(jeb.HashedKey(), f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrNotConditional)
+
+	_, err = m.ResolveConditional(jeb.HashedKey(), f9mission.VoteAbort)
+	c.Check(err, Equals, f9mission.ErrInvalidResolution)
+
+	//
+	// Test that unresolved conditional votes aren't ready, and are
+	// reported separately
+	//
+	_, err = m.UpdateVote(bill.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	ready, err = m.UpdateVoteConditional(jeb.HashedKey(), "once the weather clears")
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+
+	tally, ready := m.Tally()
+	c.Check(ready, Equals, false)
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 1, f9mission.VoteConditional: 1})
+	c.Check(m.Reasons()[jeb.HashedKey()], Equals, "once the weather clears")
+
+	status := m.CrewStatus()[jeb.HashedKey()]
+	c.Check(status.Vote, Equals, "Conditional")
+	c.Check(status.Decided, Equals, false)
+	c.Check(status.Go, Equals, false)
+
+	//
+	// Test that resolving the condition to Yes starts the blastoff
+	//
+	ready, err = m.ResolveConditional(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(m.Reasons()[jeb.HashedKey()], Equals, "once the weather clears")
+
+	c.Assert(len(events), Equals, 3)
+	c.Check(events[2], Equals, f9mission.VoteEvent{
+		HashedKey: jeb.HashedKey(),
+		Vote:      f9mission.VoteYes,
+		Reason:    "once the weather clears",
+		Resolved:  true,
+	})
+
+	_, err = m.ResolveConditional(jeb.HashedKey(), f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrNotConditional)
+}
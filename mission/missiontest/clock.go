@@ -0,0 +1,146 @@
+// Package f9missiontest provides helpers for testing code that uses falcon9
+// missions.
+package f9missiontest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+)
+
+// Clock is a f9mission.Clock whose time only changes when it's told to. Use
+// it as the Clock mission parameter to test timer-driven behavior, such as the
+// blastoff cooldown, without waiting for real time to pass.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers map[*timer]struct{}
+}
+
+// NewClock returns a Clock set to the time now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{
+		now:    now,
+		timers: make(map[*timer]struct{}),
+	}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a Timer that sends the clock's time on its channel once
+// the clock has been advanced by at least the duration d.
+func (c *Clock) NewTimer(d time.Duration) f9mission.Timer {
+	return c.newTimer(d, nil)
+}
+
+// AfterFunc returns a Timer that calls f once the clock has been advanced by
+// at least the duration d. The function is called by Advance(), in the
+// goroutine that called it.
+func (c *Clock) AfterFunc(d time.Duration, f func()) f9mission.Timer {
+	return c.newTimer(d, f)
+}
+
+func (c *Clock) newTimer(d time.Duration, f func()) *timer {
+	t := &timer{
+		clock: c,
+		ch:    make(chan time.Time, 1),
+		f:     f,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t.when = c.now.Add(d)
+	c.timers[t] = struct{}{}
+
+	return t
+}
+
+// Advance moves the clock forward by the duration d, and fires the timers
+// that have expired in the order they expire. The functions given to
+// AfterFunc() are called synchronously, after the clock has been moved.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+
+	c.now = c.now.Add(d)
+
+	var expired []*timer
+
+	for t := range c.timers {
+		if !t.when.After(c.now) {
+			expired = append(expired, t)
+			delete(c.timers, t)
+		}
+	}
+
+	now := c.now
+
+	c.mu.Unlock()
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].when.Before(expired[j].when) })
+
+	for _, t := range expired {
+		if t.f != nil {
+			t.f()
+			continue
+		}
+
+		select {
+		case t.ch <- now:
+		default:
+		}
+	}
+}
+
+// Timers returns the number of timers that haven't fired or been stopped.
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+type timer struct {
+	clock *Clock
+	when  time.Time
+	ch    chan time.Time
+	f     func()
+}
+
+func (t *timer) C() <-chan time.Time {
+	if t.f != nil {
+		return nil
+	}
+
+	return t.ch
+}
+
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	_, active := t.clock.timers[t]
+	delete(t.clock.timers, t)
+
+	return active
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	_, active := t.clock.timers[t]
+
+	t.when = t.clock.now.Add(d)
+	t.clock.timers[t] = struct{}{}
+
+	return active
+}
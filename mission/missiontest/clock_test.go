@@ -0,0 +1,73 @@
+package f9missiontest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+type TestSuite struct{}
+
+var _ = Suite(&TestSuite{})
+
+func Test(t *testing.T) { TestingT(t) }
+
+var epoch = time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func (*TestSuite) TestClock_Now(c *C) {
+	clock := f9missiontest.NewClock(epoch)
+	c.Check(clock.Now(), Equals, epoch)
+
+	clock.Advance(time.Minute)
+	c.Check(clock.Now(), Equals, epoch.Add(time.Minute))
+}
+
+func (*TestSuite) TestClock_NewTimer(c *C) {
+	clock := f9missiontest.NewClock(epoch)
+	t := clock.NewTimer(time.Second * 10)
+
+	clock.Advance(time.Second * 9)
+
+	select {
+	case <-t.C():
+		c.Fatal("the timer fired early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	c.Check(<-t.C(), Equals, epoch.Add(time.Second*10))
+	c.Check(clock.Timers(), Equals, 0)
+	c.Check(t.Stop(), Equals, false)
+
+	//
+	// Test that a reset timer fires again
+	//
+	c.Check(t.Reset(time.Second), Equals, false)
+	clock.Advance(time.Second)
+	c.Check(<-t.C(), Equals, epoch.Add(time.Second*11))
+}
+
+func (*TestSuite) TestClock_AfterFunc(c *C) {
+	var fired []int
+
+	clock := f9missiontest.NewClock(epoch)
+
+	clock.AfterFunc(time.Second*2, func() { fired = append(fired, 2) })
+	clock.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+	t := clock.AfterFunc(time.Second*3, func() { fired = append(fired, 3) })
+
+	c.Check(t.C(), IsNil)
+	c.Check(clock.Timers(), Equals, 3)
+
+	//
+	// Test that stopped timers don't fire, and the rest fire in order
+	//
+	c.Check(t.Stop(), Equals, true)
+	c.Check(t.Stop(), Equals, false)
+
+	clock.Advance(time.Minute)
+	c.Check(fired, DeepEquals, []int{1, 2})
+	c.Check(clock.Timers(), Equals, 0)
+}
@@ -0,0 +1,159 @@
+package f9mission
+
+// InterfaceRecycle is the interface for re-running a mission's Go/No-Go once
+// the previous one was aborted or has finished.
+type InterfaceRecycle interface {
+	// Reset returns the mission to StateReady after a Go/No-Go has been
+	// aborted or has finished, clearing the votes. If a Go/No-Go is in
+	// progress this will return a ErrMissionInProgress error.
+	Reset() error
+
+	// Recycle re-runs the Go/No-Go with the same crew after the previous
+	// one was aborted or has finished. If preserveYes is true, the crew who
+	// voted "Go" keep their vote. The bool value returned indicates whether
+	// the preserved votes are enough to proceed with blastoff.
+	Recycle(preserveYes bool) (bool, error)
+}
+
+// InterfaceAbort is the interface for aborting a mission's Go/No-Go or
+// blastoff.
+type InterfaceAbort interface {
+	// Abort aborts the Go/No-Go or blastoff in progress. If one isn't in
+	// progress this will return a ErrVotingNotInProgress error.
+	Abort() error
+}
+
+// Abort aborts the Go/No-Go or blastoff in progress, in the same way as a
+// crew member voting VoteAbort. If one isn't in progress this will return a
+// ErrVotingNotInProgress error.
+func (m *Mission) Abort() error {
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	// this is deferred first so that it runs after the mutexes are unlocked
+	defer m.flushStateChanges()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	switch m.CurrentState() {
+	case StateVoting, StateBlastoffing:
+		return m.abort()
+	default:
+		return ErrVotingNotInProgress
+	}
+}
+
+// Reset returns the mission to StateReady after a Go/No-Go has been aborted
+// or has finished, clearing the votes and reasons. Resetting a mission that's
+// already in StateReady does nothing. If a Go/No-Go is in progress this will
+// return a ErrMissionInProgress error.
+func (m *Mission) Reset() error {
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	// this is deferred first so that it runs after the mutexes are unlocked
+	defer m.flushStateChanges()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	switch m.CurrentState() {
+	case StateReady:
+		return nil
+	case StateVoting, StateBlastoffing:
+		return ErrMissionInProgress
+	}
+
+	if err := m.transition(StateReady); err != nil {
+		return err
+	}
+
+	m.gngResults = nil
+	m.gngReasons = nil
+	m.resetStages()
+
+	return nil
+}
+
+// Recycle re-runs the Go/No-Go with the same crew after the previous one was
+// aborted or has finished. If preserveYes is true, the crew who voted "Go" in
+// the previous attempt keep their vote and its reason, and everyone else
+// starts again; otherwise all votes are cleared, the same as Initiate(). A
+// staged launch sequence starts again from the first stage, and only the
+// votes from the first stage are preserved.
+//
+// The bool value returned indicates whether the preserved votes are enough to
+// proceed with blastoff, in which case the blastoff begins immediately (or
+// once the launch window opens). If a Go/No-Go is in progress this will
+// return a ErrMissionInProgress error.
+func (m *Mission) Recycle(preserveYes bool) (bool, error) {
+	if m.closed() {
+		return false, ErrMissionClosed
+	}
+
+	// this is deferred first so that it runs after the mutexes are unlocked
+	defer m.flushStateChanges()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	if len(m.crew) == 0 {
+		return false, ErrNoAssignedCrew
+	}
+
+	switch m.CurrentState() {
+	case StateVoting, StateBlastoffing:
+		return false, ErrMissionInProgress
+	}
+
+	if err := m.checkWindow(); err != nil {
+		return false, err
+	}
+
+	if state := m.CurrentState(); state == StateAborted || state == StateFinished {
+		if err := m.transition(StateReady); err != nil {
+			return false, err
+		}
+	}
+
+	previous, previousReasons := m.gngResults, m.gngReasons
+
+	// a staged launch sequence starts again from the first stage
+	if len(m.stageResults) > 0 {
+		previous, previousReasons = m.stageResults[0].Results, m.stageResults[0].Reasons
+	}
+
+	m.resetStages()
+
+	results, reasons := make(Results), make(Reasons)
+
+	if preserveYes {
+		for hashedKey, vote := range previous {
+			if _, ok := m.crew[hashedKey]; !ok || vote != VoteYes {
+				continue
+			}
+
+			results[hashedKey] = vote
+
+			if reason, ok := previousReasons[hashedKey]; ok {
+				reasons[hashedKey] = reason
+			}
+		}
+	}
+
+	m.gngResults, m.gngReasons = results, reasons
+
+	if err := m.transition(StateVoting); err != nil {
+		return false, err
+	}
+
+	m.startScrubTimer()
+
+	return m.proceed()
+}
@@ -0,0 +1,133 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+// newResetMission returns a mission using GNGAll with three crew members,
+// and their HashedKeys.
+func newResetMission(c *C, clock f9mission.Clock) (*f9mission.Mission, []string) {
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGAll,
+		BlastoffingCooldown: time.Second * 10,
+		Clock:               clock,
+	})
+	c.Assert(err, IsNil)
+
+	return m, addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman", "Bob Kerman")
+}
+
+func (*TestSuite) TestMission_Reset(c *C) {
+	var err error
+
+	m, keys := newResetMission(c, nil)
+
+	// resetting a ready mission does nothing
+	c.Check(m.Reset(), IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateReady)
+
+	c.Assert(m.Initiate(), IsNil)
+	c.Check(m.Reset(), Equals, f9mission.ErrMissionInProgress)
+
+	_, err = m.UpdateVoteReason(keys[0], f9mission.VoteAbort, "fuel leak")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.Reset(), IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateReady)
+	c.Check(m.Reasons(), IsNil)
+
+	tally, ready := m.Tally()
+	c.Check(tally, IsNil)
+	c.Check(ready, Equals, false)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrVotingNotInProgress)
+}
+
+func (*TestSuite) TestMission_Recycle(c *C) {
+	var ready bool
+	var err error
+
+	clock := f9missiontest.NewClock(time.Now())
+	m, keys := newResetMission(c, clock)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.Recycle(true)
+	c.Check(err, Equals, f9mission.ErrMissionInProgress)
+
+	_, err = m.UpdateVoteReason(keys[0], f9mission.VoteYes, "all green")
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[2], f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+
+	//
+	// Test that the Yes votes, and their reasons, are preserved
+	//
+	ready, err = m.Recycle(true)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	tally, _ := m.Tally()
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 1})
+	c.Check(m.Reasons(), DeepEquals, f9mission.Reasons{keys[0]: "all green"})
+
+	//
+	// Test that the blastoff starts if the preserved votes are enough
+	//
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[2], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	clock.Advance(time.Second * 10)
+	c.Assert(m.CurrentState(), Equals, f9mission.StateFinished)
+
+	ready, err = m.Recycle(true)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	clock.Advance(time.Second * 10)
+	c.Assert(m.CurrentState(), Equals, f9mission.StateFinished)
+
+	//
+	// Test that without preserving, all votes are cleared
+	//
+	ready, err = m.Recycle(false)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+
+	tally, _ = m.Tally()
+	c.Check(len(tally), Equals, 0)
+	c.Check(m.Reasons(), DeepEquals, f9mission.Reasons{})
+
+	//
+	// Test that crew who left don't keep their vote
+	//
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+
+	_, err = m.RemoveCrew(keys[0])
+	c.Assert(err, IsNil)
+
+	_, err = m.Recycle(true)
+	c.Assert(err, IsNil)
+
+	tally, _ = m.Tally()
+	c.Check(len(tally), Equals, 0)
+}
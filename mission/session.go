@@ -0,0 +1,228 @@
+package f9mission
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+)
+
+// DefaultResumeWindow is the amount of time a disconnected crew member has to
+// resume their session, if the ResumeWindow mission parameter isn't set.
+const DefaultResumeWindow = time.Second * 30
+
+// ErrInvalidSession is the error returned from Resume() if the session token
+// isn't known to the mission, or if it belongs to a different crew member.
+var ErrInvalidSession = errors.New("the session token is not valid for this mission")
+
+// ErrSessionExpired is the error returned from Resume() if the crew member
+// disconnected longer ago than the mission's resume window. The crew member
+// was removed from the mission when their session expired, and needs to be
+// added again using AddCrew().
+var ErrSessionExpired = errors.New("the resume window for this session has elapsed")
+
+// InterfaceSessions is the interface for crew sessions. A session token is
+// issued to each crew member when they are added to the mission, and can be
+// used to reclaim their crew slot (and their vote) after a network
+// interruption.
+type InterfaceSessions interface {
+	// SessionToken returns the session token issued to the crew member when
+	// they were added to the mission. If the crew member is not assigned to
+	// this mission, this will return a ErrCrewMemberNotPresent error.
+	SessionToken(hashedKey string) (string, error)
+
+	// Disconnect marks the crew member's session as disconnected. This
+	// starts the resume window, within which the crew member can resume
+	// their session. The crew member remains assigned to the mission until
+	// the resume window elapses, and is then removed.
+	Disconnect(hashedKey string) error
+
+	// Resume uses a session token to reclaim a crew slot. The crew member
+	// replaces the existing one, keeping their vote, without aborting a
+	// blastoff that's in progress. The crew member's HashedKey must match the
+	// one the token was issued to.
+	Resume(token string, crew f9crew.Interface) error
+}
+
+type session struct {
+	hashedKey      string
+	disconnected   bool
+	disconnectedAt time.Time
+	expiry         Timer
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// issueSession creates a new session for the crew member, invalidating any
+// previous session they had. The crewMu must be held by the caller.
+func (m *Mission) issueSession(hashedKey string) error {
+	token, err := newSessionToken()
+
+	if err != nil {
+		return err
+	}
+
+	m.dropSession(hashedKey)
+	delete(m.expired, hashedKey)
+
+	m.sessions[token] = &session{hashedKey: hashedKey}
+	m.tokens[hashedKey] = token
+
+	return nil
+}
+
+// dropSession invalidates the crew member's session, if they have one.
+// The crewMu must be held by the caller.
+func (m *Mission) dropSession(hashedKey string) {
+	if token, ok := m.tokens[hashedKey]; ok {
+		stopTimer(&m.sessions[token].expiry)
+		delete(m.sessions, token)
+		delete(m.tokens, hashedKey)
+	}
+}
+
+// SessionToken returns the session token issued to the crew member when
+// they were added to the mission. If the crew member is not assigned to
+// this mission, this will return a ErrCrewMemberNotPresent error.
+func (m *Mission) SessionToken(hashedKey string) (string, error) {
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	token, ok := m.tokens[hashedKey]
+
+	if !ok {
+		return "", ErrCrewMemberNotPresent
+	}
+
+	return token, nil
+}
+
+// Disconnect marks the crew member's session as disconnected. This starts
+// the resume window, within which the crew member can resume their session.
+// The crew member remains assigned to the mission until the resume window
+// elapses, and is then removed in the same way as RemoveCrew(). If the
+// mission has been closed, this will return a ErrMissionClosed error.
+func (m *Mission) Disconnect(hashedKey string) error {
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	// this is checked with the crewMu held, so that Close() stops the expiry
+	// timers of any sessions disconnected before it
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	token, ok := m.tokens[hashedKey]
+
+	if !ok {
+		return ErrCrewMemberNotPresent
+	}
+
+	s := m.sessions[token]
+
+	// if they were already disconnected, don't extend the window
+	if !s.disconnected {
+		s.disconnected = true
+		s.disconnectedAt = m.clock.Now()
+
+		// the session expires once more than the resume window has passed
+		s.expiry = m.clock.AfterFunc(m.resumeWindow+time.Nanosecond, func() { m.expireSession(token) })
+	}
+
+	return nil
+}
+
+// expireSession removes the crew member whose session has the token from the
+// mission, if they're still disconnected. The token is remembered, so that
+// Resume() can tell the crew member that their session expired.
+func (m *Mission) expireSession(token string) {
+	if m.closed() {
+		return
+	}
+
+	var diff f9crew.ManifestDiff
+
+	// these are deferred first so that they run after the mutex is unlocked
+	defer m.flushStateChanges()
+	defer func() { m.crewChanged(diff) }()
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	s, ok := m.sessions[token]
+
+	if !ok || !s.disconnected {
+		return
+	}
+
+	s.expiry = nil
+
+	diff.Removed = f9crew.Manifest{m.removeCrew(s.hashedKey)}
+	m.expired[s.hashedKey] = token
+
+	if m.CurrentState() == StateVoting {
+		m.proceed()
+	}
+}
+
+// Resume uses a session token to reclaim a crew slot. The crew member
+// replaces the existing one, keeping their vote, without aborting a
+// blastoff that's in progress. The crew member's HashedKey must match the
+// one the token was issued to.
+//
+// If the crew member's session expired because they disconnected longer ago
+// than the resume window, this returns a ErrSessionExpired error.
+func (m *Mission) Resume(token string, crew f9crew.Interface) error {
+	if m.crew == nil {
+		return errUseNewMission
+	}
+
+	if crew == nil {
+		return errors.New("a crew member cannot be nil")
+	}
+
+	if m.closed() {
+		return ErrMissionClosed
+	}
+
+	var diff f9crew.ManifestDiff
+
+	// this is deferred first so that it runs after the mutex is unlocked
+	defer func() { m.crewChanged(diff) }()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	// the crew member is told once that their session expired
+	if expired, ok := m.expired[crew.HashedKey()]; ok && expired == token {
+		delete(m.expired, crew.HashedKey())
+		return ErrSessionExpired
+	}
+
+	s, ok := m.sessions[token]
+
+	if !ok || s.hashedKey != crew.HashedKey() {
+		return ErrInvalidSession
+	}
+
+	diff = f9crew.Manifest{m.crew[s.hashedKey]}.Diff(f9crew.Manifest{crew})
+
+	m.crew[s.hashedKey] = crew
+	s.disconnected = false
+	stopTimer(&s.expiry)
+
+	return nil
+}
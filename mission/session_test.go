@@ -0,0 +1,181 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestMission_SessionToken(c *C) {
+	var token, token2 string
+	var err error
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+
+	//
+	// Test that crew who aren't present have no token
+	//
+	token, err = m.SessionToken("5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9")
+	c.Check(err, Equals, f9mission.ErrCrewMemberNotPresent)
+	c.Check(token, Equals, "")
+
+	crew, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(crew, false), IsNil)
+
+	token, err = m.SessionToken(crew.HashedKey())
+	c.Assert(err, IsNil)
+	c.Check(len(token), Equals, 32)
+
+	//
+	// Test that replacing the crew member issues a new token
+	//
+	c.Assert(m.AddCrew(crew, true), IsNil)
+
+	token2, err = m.SessionToken(crew.HashedKey())
+	c.Assert(err, IsNil)
+	c.Check(token2, Not(Equals), token)
+	c.Check(m.Resume(token, crew), Equals, f9mission.ErrInvalidSession)
+
+	//
+	// Test that removing the crew member invalidates their token
+	//
+	_, err = m.RemoveCrew(crew.HashedKey())
+	c.Assert(err, IsNil)
+
+	_, err = m.SessionToken(crew.HashedKey())
+	c.Check(err, Equals, f9mission.ErrCrewMemberNotPresent)
+	c.Check(m.Resume(token2, crew), Equals, f9mission.ErrInvalidSession)
+}
+
+func (*TestSuite) TestMission_Resume(c *C) {
+	var ok bool
+	var err error
+
+	var diffs []f9crew.ManifestDiff
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		ResumeWindow: time.Millisecond * 50,
+		Clock:        clock,
+		OnCrewChange: func(diff f9crew.ManifestDiff) { diffs = append(diffs, diff) },
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(bill, false), IsNil)
+
+	token, err := m.SessionToken(jeb.HashedKey())
+	c.Assert(err, IsNil)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	ok, err = m.UpdateVote(bill.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	//
+	// Test that a token can't be used by a different crew member
+	//
+	c.Check(m.Resume(token, bill), Equals, f9mission.ErrInvalidSession)
+	c.Check(m.Resume("bogus", jeb), Equals, f9mission.ErrInvalidSession)
+
+	//
+	// Test that resuming during blastoff keeps the vote and doesn't abort
+	//
+	c.Assert(m.Disconnect(jeb.HashedKey()), IsNil)
+
+	jeb2, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(m.Resume(token, jeb2), IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	tally, ok := m.Tally()
+	c.Check(ok, Equals, true)
+	c.Check(tally[f9mission.VoteYes], Equals, 2)
+
+	//
+	// Test that the token expires once the resume window elapses
+	//
+	c.Assert(m.Disconnect(jeb.HashedKey()), IsNil)
+
+	clock.Advance(time.Millisecond * 50)
+	c.Check(m.Resume(token, jeb2), IsNil)
+
+	c.Assert(m.Disconnect(jeb.HashedKey()), IsNil)
+
+	diffs = nil
+
+	clock.Advance(time.Millisecond * 51)
+
+	//
+	// Test that the crew member is removed once their session expires
+	//
+	c.Check(len(m.Crew()), Equals, 1)
+	c.Check(m.Crew()[0].HashedKey(), Equals, bill.HashedKey())
+	c.Check(diffs, DeepEquals, []f9crew.ManifestDiff{{Removed: f9crew.Manifest{jeb2}}})
+
+	_, ok = m.CrewStatus()[jeb.HashedKey()]
+	c.Check(ok, Equals, false)
+
+	_, err = m.SessionToken(jeb.HashedKey())
+	c.Check(err, Equals, f9mission.ErrCrewMemberNotPresent)
+
+	c.Check(m.Resume(token, jeb2), Equals, f9mission.ErrSessionExpired)
+	c.Check(m.Resume(token, jeb2), Equals, f9mission.ErrInvalidSession)
+
+	c.Check(m.AddCrew(jeb2, false), IsNil)
+
+	c.Check(m.Disconnect("bogus"), Equals, f9mission.ErrCrewMemberNotPresent)
+}
+
+func (*TestSuite) TestMission_ExpireSession_Voting(c *C) {
+	var ok bool
+	var err error
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		ResumeWindow: time.Millisecond * 50,
+		Clock:        clock,
+	})
+	c.Assert(err, IsNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+
+	bill, err := f9crew.NewCrewMember("Bill Kerman", "1")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(bill, false), IsNil)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	ok, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	//
+	// Test that the blastoff begins once the crew member yet to vote has
+	// their session expire
+	//
+	c.Assert(m.Disconnect(bill.HashedKey()), IsNil)
+
+	clock.Advance(time.Millisecond * 51)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+}
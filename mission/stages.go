@@ -0,0 +1,211 @@
+package f9mission
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotInStage is the error returned when a crew member votes in a stage of
+// the launch sequence that they aren't part of.
+var ErrNotInStage = errors.New("the crew member is not part of the current stage")
+
+// Stage is one stage of a multi-stage launch sequence. Each stage has its own
+// Go/No-Go, which is voted on by the stage's crew using the stage's Go/No-Go
+// setting. The stages run in order, and the blastoff begins once the last one
+// is ready. Voting to abort in any stage aborts the whole sequence.
+type Stage struct {
+	// Name is the name of the stage, e.g. "infra".
+	Name string
+
+	// Crew is the HashedKey of each crew member who votes in this stage.
+	// Crew in the list who haven't joined the mission count against the
+	// stage's Go/No-Go, in the same way as crew on the mission's roster. If
+	// empty, all of the mission's crew vote in this stage.
+	Crew []string
+
+	// GoNoGo is the Go/No-Go setting for the stage.
+	GoNoGo GNGSetting
+}
+
+// StageResult is the outcome of a stage of the launch sequence that was
+// ready, and so moved on to the next one.
+type StageResult struct {
+	Name    string
+	Results Results
+	Reasons Reasons
+}
+
+// InterfaceStages is the interface for missions with a multi-stage launch
+// sequence.
+type InterfaceStages interface {
+	// Stages returns the stages of the mission's launch sequence, or nil if
+	// it doesn't have stages.
+	Stages() []Stage
+
+	// CurrentStage returns the index of the stage of the launch sequence
+	// being voted on, or -1 if the mission doesn't have stages.
+	CurrentStage() int
+
+	// StageResults returns the results of the completed stages of the
+	// current launch sequence.
+	StageResults() []StageResult
+}
+
+func copyStages(stages []Stage) ([]Stage, error) {
+	if len(stages) == 0 {
+		return nil, nil
+	}
+
+	copied := make([]Stage, len(stages))
+	names := make(map[string]struct{}, len(stages))
+
+	for i, s := range stages {
+		if s.Name == "" {
+			return nil, fmt.Errorf("stage %d must have a name", i)
+		}
+
+		if _, ok := names[s.Name]; ok {
+			return nil, fmt.Errorf("stage name %q is used more than once", s.Name)
+		}
+
+		names[s.Name] = struct{}{}
+
+		copied[i] = s
+		copied[i].Crew = append([]string(nil), s.Crew...)
+	}
+
+	return copied, nil
+}
+
+// currentStage returns the stage of the launch sequence that's being voted
+// on, or nil if the mission doesn't have stages. The gngMu must be held by the
+// caller.
+func (m *Mission) currentStage() *Stage {
+	if len(m.stages) == 0 {
+		return nil
+	}
+
+	return &m.stages[m.stage]
+}
+
+// inStage returns whether the crew member votes in the current stage. The
+// gngMu must be held by the caller.
+func (m *Mission) inStage(hashedKey string) bool {
+	stage := m.currentStage()
+
+	if stage == nil || len(stage.Crew) == 0 {
+		return true
+	}
+
+	for _, key := range stage.Crew {
+		if key == hashedKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gngSetting returns the Go/No-Go setting of the current stage, or the
+// mission's if it doesn't have stages. The gngMu must be held by the caller.
+func (m *Mission) gngSetting() GNGSetting {
+	if stage := m.currentStage(); stage != nil {
+		return stage.GoNoGo
+	}
+
+	return m.gng
+}
+
+// electorate returns the HashedKeys of the crew who vote in the current
+// stage and have joined the mission, and those who haven't joined. The gngMu
+// and crewMu must be held by the caller.
+func (m *Mission) electorate() (present, missing []string) {
+	stage := m.currentStage()
+
+	if stage == nil || len(stage.Crew) == 0 {
+		for hashedKey := range m.crew {
+			present = append(present, hashedKey)
+		}
+
+		for _, crew := range m.missing() {
+			missing = append(missing, crew.HashedKey())
+		}
+
+		return present, missing
+	}
+
+	for _, hashedKey := range stage.Crew {
+		if _, ok := m.crew[hashedKey]; ok {
+			present = append(present, hashedKey)
+		} else {
+			missing = append(missing, hashedKey)
+		}
+	}
+
+	return present, missing
+}
+
+// advanceStage records the results of the current stage, and moves on to the
+// next one. The gngMu must be held by the caller.
+func (m *Mission) advanceStage() {
+	m.stageResults = append(m.stageResults, StageResult{
+		Name:    m.stages[m.stage].Name,
+		Results: m.gngResults,
+		Reasons: m.gngReasons,
+	})
+
+	m.stage++
+	m.gngResults = make(Results)
+	m.gngReasons = make(Reasons)
+}
+
+// resetStages returns the launch sequence to its first stage. The gngMu must
+// be held by the caller.
+func (m *Mission) resetStages() {
+	m.stage = 0
+	m.stageResults = nil
+}
+
+// Stages returns the stages of the mission's launch sequence, or nil if it
+// doesn't have stages.
+func (m *Mission) Stages() []Stage {
+	stages, _ := copyStages(m.stages)
+	return stages
+}
+
+// CurrentStage returns the index of the stage of the launch sequence that's
+// being voted on, or that was being voted on when the Go/No-Go ended. If the
+// mission doesn't have stages this returns -1.
+func (m *Mission) CurrentStage() int {
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	if len(m.stages) == 0 {
+		return -1
+	}
+
+	return m.stage
+}
+
+// StageResults returns the results of the stages of the current launch
+// sequence that have been completed, in order.
+func (m *Mission) StageResults() []StageResult {
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	results := make([]StageResult, len(m.stageResults))
+
+	for i, sr := range m.stageResults {
+		results[i] = StageResult{Name: sr.Name, Results: make(Results), Reasons: make(Reasons)}
+
+		for k, v := range sr.Results {
+			results[i].Results[k] = v
+		}
+
+		for k, v := range sr.Reasons {
+			results[i].Reasons[k] = v
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,153 @@
+package f9mission_test
+
+import (
+	"github.com/theckman/falcon9/mission"
+	. "gopkg.in/check.v1"
+)
+
+// newStagedMission returns a mission with four crew members and a three stage
+// launch sequence: "infra" (0 and 1, GNGAll), "app" (2 and 3, GNGQuorum), and
+// "launch" (everyone, GNGQuorum). It also returns the crew's HashedKeys.
+func newStagedMission(c *C) (*f9mission.Mission, []string) {
+	// the stages need the HashedKeys before the crew can be added
+	crew, keys := newCrew(c, "Jebediah Kerman", "Bill Kerman", "Bob Kerman", "Valentina Kerman")
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		Stages: []f9mission.Stage{
+			{Name: "infra", Crew: keys[:2], GoNoGo: f9mission.GNGAll},
+			{Name: "app", Crew: keys[2:], GoNoGo: f9mission.GNGQuorum},
+			{Name: "launch", GoNoGo: f9mission.GNGQuorum},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	for _, cm := range crew {
+		c.Assert(m.AddCrew(cm, false), IsNil)
+	}
+
+	return m, keys
+}
+
+func (*TestSuite) TestNewMission_Stages(c *C) {
+	var err error
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Stages: []f9mission.Stage{{}}})
+	c.Check(err, ErrorMatches, "stage 0 must have a name")
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Stages: []f9mission.Stage{{Name: "a"}, {Name: "a"}}})
+	c.Check(err, ErrorMatches, `stage name "a" is used more than once`)
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+	c.Check(m.Stages(), IsNil)
+	c.Check(m.CurrentStage(), Equals, -1)
+}
+
+func (*TestSuite) TestMission_Stages(c *C) {
+	var ready bool
+	var err error
+
+	m, keys := newStagedMission(c)
+
+	stages := m.Stages()
+	c.Assert(len(stages), Equals, 3)
+	c.Check(stages[1].Name, Equals, "app")
+
+	c.Assert(m.Initiate(), IsNil)
+	c.Check(m.CurrentStage(), Equals, 0)
+
+	//
+	// Test that only the stage's crew can vote
+	//
+	_, err = m.UpdateVote(keys[2], f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrNotInStage)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentStage(), Equals, 0)
+
+	//
+	// Test that the next stage starts once the stage is ready
+	//
+	ready, err = m.UpdateVoteReason(keys[1], f9mission.VoteYes, "servers are up")
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+	c.Check(m.CurrentStage(), Equals, 1)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	tally, _ := m.Tally()
+	c.Check(len(tally), Equals, 0)
+
+	results := m.StageResults()
+	c.Assert(len(results), Equals, 1)
+	c.Check(results[0].Name, Equals, "infra")
+	c.Check(results[0].Results, DeepEquals, f9mission.Results{keys[0]: f9mission.VoteYes, keys[1]: f9mission.VoteYes})
+	c.Check(results[0].Reasons, DeepEquals, f9mission.Reasons{keys[1]: "servers are up"})
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrNotInStage)
+
+	// with two crew, the quorum falls back to all
+	_, err = m.UpdateVote(keys[2], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentStage(), Equals, 1)
+
+	_, err = m.UpdateVote(keys[3], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentStage(), Equals, 2)
+
+	//
+	// Test that the last stage starts the blastoff
+	//
+	for i, key := range keys[:2] {
+		ready, err = m.UpdateVote(key, f9mission.VoteYes)
+		c.Assert(err, IsNil)
+		c.Check(ready, Equals, false, Commentf("vote %d", i))
+	}
+
+	ready, err = m.UpdateVote(keys[2], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(m.CurrentStage(), Equals, 2)
+	c.Check(len(m.StageResults()), Equals, 2)
+
+	c.Assert(m.Close(), IsNil)
+}
+
+func (*TestSuite) TestMission_StagesAbort(c *C) {
+	var err error
+
+	m, keys := newStagedMission(c)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	//
+	// Test that an abort in any stage aborts the whole sequence
+	//
+	_, err = m.UpdateVote(keys[3], f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+	c.Check(m.CurrentStage(), Equals, 1)
+
+	//
+	// Test that recycling starts again from the first stage, with its votes
+	//
+	_, err = m.Recycle(true)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentStage(), Equals, 1)
+	c.Check(len(m.StageResults()), Equals, 1)
+
+	_, err = m.UpdateVote(keys[3], f9mission.VoteAbort)
+	c.Assert(err, IsNil)
+
+	_, err = m.Recycle(false)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentStage(), Equals, 0)
+	c.Check(len(m.StageResults()), Equals, 0)
+}
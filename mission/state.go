@@ -0,0 +1,102 @@
+package f9mission
+
+import (
+	"sync"
+
+	"github.com/theckman/go-fsm"
+)
+
+// StateChange is a change of a mission's state.
+type StateChange struct {
+	MissionID uint32
+	From      fsm.State
+	To        fsm.State
+}
+
+// InterfaceStateChanges is the interface for watching the state of a mission
+// change.
+type InterfaceStateChanges interface {
+	// WatchState calls f each time the state of the mission changes, until
+	// the returned function is called. It's called synchronously, after the
+	// mission's locks have been released.
+	WatchState(f func(StateChange)) (stop func())
+}
+
+type stateWatchers struct {
+	pending  []StateChange
+	watchers map[*func(StateChange)]struct{}
+	mu       sync.Mutex
+}
+
+// transition moves the state machine to the new state, and queues the change
+// to be sent to the state watchers by flushStateChanges().
+func (m *Mission) transition(to fsm.State) error {
+	from := m.CurrentState()
+
+	if err := m.stateMachine.StateTransition(to); err != nil {
+		return err
+	}
+
+	m.state.mu.Lock()
+	m.state.pending = append(m.state.pending, StateChange{MissionID: m.id, From: from, To: to})
+	m.state.mu.Unlock()
+
+	return nil
+}
+
+// flushStateChanges sends the queued state changes to the OnStateChange
+// mission parameter and the state watchers, and then the queued readiness
+// changes to the Coordinate() function. The mission's locks must not be held
+// by the caller, so it's deferred before they're taken.
+func (m *Mission) flushStateChanges() {
+	for {
+		m.state.mu.Lock()
+
+		if len(m.state.pending) == 0 {
+			m.state.mu.Unlock()
+			m.flushReadiness()
+			return
+		}
+
+		change := m.state.pending[0]
+		m.state.pending = m.state.pending[1:]
+
+		watchers := make([]func(StateChange), 0, len(m.state.watchers))
+
+		for f := range m.state.watchers {
+			watchers = append(watchers, *f)
+		}
+
+		m.state.mu.Unlock()
+
+		if m.onStateChange != nil {
+			m.onStateChange(change)
+		}
+
+		for _, f := range watchers {
+			f(change)
+		}
+	}
+}
+
+// WatchState calls f each time the state of the mission changes, until the
+// returned function is called. It's called synchronously, after the mission's
+// locks have been released, so it may use the mission.
+func (m *Mission) WatchState(f func(StateChange)) (stop func()) {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	if m.state.watchers == nil {
+		m.state.watchers = make(map[*func(StateChange)]struct{})
+	}
+
+	key := &f
+	m.state.watchers[key] = struct{}{}
+
+	return func() {
+		m.state.mu.Lock()
+		defer m.state.mu.Unlock()
+
+		delete(m.state.watchers, key)
+	}
+}
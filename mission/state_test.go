@@ -0,0 +1,76 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestMission_StateChanges(c *C) {
+	var changes, watched []f9mission.StateChange
+	var m *f9mission.Mission
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		ID:    42,
+		Clock: clock,
+		OnStateChange: func(change f9mission.StateChange) {
+			// this would deadlock if the mission were still locked
+			m.Tally()
+			changes = append(changes, change)
+		},
+	})
+	c.Assert(err, IsNil)
+
+	stop := m.WatchState(func(change f9mission.StateChange) {
+		watched = append(watched, change)
+	})
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second * 10)
+
+	c.Check(changes, DeepEquals, []f9mission.StateChange{
+		{MissionID: 42, From: f9mission.StateReady, To: f9mission.StateVoting},
+		{MissionID: 42, From: f9mission.StateVoting, To: f9mission.StateBlastoffing},
+		{MissionID: 42, From: f9mission.StateBlastoffing, To: f9mission.StateFinished},
+	})
+	c.Check(watched, DeepEquals, changes)
+
+	//
+	// Test that stopped watchers aren't called
+	//
+	stop()
+
+	c.Assert(m.Initiate(), IsNil)
+	c.Check(len(changes), Equals, 5)
+	c.Check(len(watched), Equals, 3)
+}
+
+func (*TestSuite) TestMission_Abort(c *C) {
+	m, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+
+	c.Check(m.Abort(), Equals, f9mission.ErrVotingNotInProgress)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+
+	c.Assert(m.Initiate(), IsNil)
+	c.Assert(m.Abort(), IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+
+	c.Check(m.Abort(), Equals, f9mission.ErrVotingNotInProgress)
+}
@@ -1,5 +1,122 @@
 package f9mission
 
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// MaxReasonLength is the maximum length, in characters, of the reason that
+// can be given with a vote.
+const MaxReasonLength = 280
+
+// ErrReasonTooLong is the error returned from UpdateVoteReason() if the
+// reason is longer than MaxReasonLength.
+var ErrReasonTooLong = errors.New("the reason for a vote is too long")
+
+var errReasonNotUTF8 = errors.New("the reason for a vote must be valid UTF-8")
+
+// ErrConditionRequired is the error returned when casting a VoteConditional
+// without giving the condition.
+var ErrConditionRequired = errors.New("a conditional vote requires a condition")
+
+// ErrNotConditional is the error returned from ResolveConditional() if the
+// crew member's current vote isn't a VoteConditional.
+var ErrNotConditional = errors.New("the crew member's vote is not conditional")
+
+// ErrInvalidResolution is the error returned from ResolveConditional() if
+// the conditional vote isn't being resolved into a VoteYes or VoteNo.
+var ErrInvalidResolution = errors.New("a conditional vote can only be resolved to Yes or No")
+
+// Reasons is the type for the reasons crew members gave for their votes. The
+// key is the crew member's HashedKey. Crew who gave no reason are not present.
+type Reasons map[string]string
+
+// VoteEvent is a vote cast by a crew member. It's passed to the OnVote
+// mission parameter so that votes, and why they were cast, can be shared
+// with the rest of the crew.
+type VoteEvent struct {
+	HashedKey string
+	Vote      Vote
+	Reason    string
+
+	// Resolved is whether this vote was a conditional vote being resolved
+	// using ResolveConditional().
+	Resolved bool
+}
+
+func validateReason(reason string) error {
+	if !utf8.ValidString(reason) {
+		return errReasonNotUTF8
+	}
+
+	if utf8.RuneCountInString(reason) > MaxReasonLength {
+		return ErrReasonTooLong
+	}
+
+	return nil
+}
+
+// ErrVoteLocked is the error returned when a crew member tries to change a
+// vote that the mission's VoteLock setting doesn't allow to be changed.
+var ErrVoteLocked = errors.New("the crew member's vote is locked and cannot be changed")
+
+// VoteLock is the type that defines whether crew members can change their
+// vote once it's been cast. Voting to abort is always allowed, regardless of
+// this setting, as is resolving a conditional vote.
+type VoteLock uint8
+
+const (
+	// VoteLockNone is the default VoteLock value. Votes can be changed at any
+	// time, and changing a vote during blastoff has no effect on it.
+	VoteLockNone VoteLock = iota
+
+	// VoteLockOnCast locks each vote once it's cast. Crew can't change
+	// their mind, so abstaining is the only way to not commit to a vote.
+	VoteLockOnCast
+
+	// VoteLockOnBlastoff locks all votes once the blastoff begins. Before
+	// then, votes can be changed freely.
+	VoteLockOnBlastoff
+
+	// VoteLockAbortOnRetract allows votes to be changed at any time, but if
+	// a crew member changes a "Go" vote to anything else during blastoff
+	// the mission is aborted, as though they voted VoteAbort.
+	VoteLockAbortOnRetract
+)
+
+// checkVoteLock returns whether the crew member can change their vote. The
+// gngMu must be held by the caller.
+func (m *Mission) checkVoteLock(hashedKey string, vote Vote) error {
+	previous := m.gngResults[hashedKey]
+
+	if vote == VoteAbort || vote == previous {
+		return nil
+	}
+
+	switch m.voteLock {
+	case VoteLockOnCast:
+		if previous != VoteAbstain {
+			return ErrVoteLocked
+		}
+	case VoteLockOnBlastoff:
+		if m.CurrentState() == StateBlastoffing {
+			return ErrVoteLocked
+		}
+	}
+
+	return nil
+}
+
+// retracted returns whether changing the crew member's vote should abort the
+// mission, per the VoteLockAbortOnRetract setting. The gngMu must be held by
+// the caller.
+func (m *Mission) retracted(hashedKey string, vote Vote) bool {
+	return m.voteLock == VoteLockAbortOnRetract &&
+		m.CurrentState() == StateBlastoffing &&
+		m.gngResults[hashedKey] == VoteYes &&
+		vote != VoteYes
+}
+
 // Vote is the type for someone's vote
 type Vote uint8
 
@@ -19,6 +136,11 @@ const (
 	// countdown has began. Depending on your mission parameters, a single abort
 	// may scrub the launch.
 	VoteAbort
+
+	// VoteConditional is a Go vote that's pending on a condition, e.g. "Go
+	// once the weather clears". The condition is given as the vote's reason.
+	// Until it's resolved into a VoteYes or VoteNo it's treated as not ready.
+	VoteConditional
 )
 
 func (v Vote) String() string {
@@ -31,6 +153,8 @@ func (v Vote) String() string {
 		return "Yes"
 	case VoteAbort:
 		return "Abort"
+	case VoteConditional:
+		return "Conditional"
 	default:
 		return "Unknown"
 	}
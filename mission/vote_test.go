@@ -1,6 +1,8 @@
 package f9mission_test
 
 import (
+	"time"
+
 	"github.com/theckman/falcon9/mission"
 	. "gopkg.in/check.v1"
 )
@@ -10,5 +12,131 @@ func (*TestSuite) TestVote_String(c *C) {
 	c.Check(f9mission.VoteNo.String(), Equals, "No")
 	c.Check(f9mission.VoteYes.String(), Equals, "Yes")
 	c.Check(f9mission.VoteAbort.String(), Equals, "Abort")
+	c.Check(f9mission.VoteConditional.String(), Equals, "Conditional")
 	c.Check(f9mission.Vote(100).String(), Equals, "Unknown")
 }
+
+// newVoteLockMission returns a voting mission with two crew members, using
+// the GNGAll setting, and their HashedKeys.
+func newVoteLockMission(c *C, lock f9mission.VoteLock) (*f9mission.Mission, string, string) {
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGAll,
+		BlastoffingCooldown: time.Second * 10,
+		VoteLock:            lock,
+	})
+	c.Assert(err, IsNil)
+
+	keys := addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman")
+	c.Assert(m.Initiate(), IsNil)
+
+	return m, keys[0], keys[1]
+}
+
+func (*TestSuite) TestVoteLock_None(c *C) {
+	var err error
+
+	m, jeb, bill := newVoteLockMission(c, f9mission.VoteLockNone)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(bill, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	// changing your mind during blastoff has no effect
+	_, err = m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Check(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteAbort)
+	c.Check(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+}
+
+func (*TestSuite) TestVoteLock_OnCast(c *C) {
+	var err error
+
+	m, jeb, bill := newVoteLockMission(c, f9mission.VoteLockOnCast)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrVoteLocked)
+
+	// casting the same vote again is not a change
+	_, err = m.UpdateVoteReason(jeb, f9mission.VoteNo, "fuel leak")
+	c.Check(err, IsNil)
+
+	//
+	// Test that conditional votes can still be resolved
+	//
+	_, err = m.UpdateVoteConditional(bill, "once the weather clears")
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(bill, f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrVoteLocked)
+
+	_, err = m.ResolveConditional(bill, f9mission.VoteYes)
+	c.Check(err, IsNil)
+
+	//
+	// Test that aborting is always allowed
+	//
+	_, err = m.UpdateVote(jeb, f9mission.VoteAbort)
+	c.Check(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+}
+
+func (*TestSuite) TestVoteLock_OnBlastoff(c *C) {
+	var err error
+
+	m, jeb, bill := newVoteLockMission(c, f9mission.VoteLockOnBlastoff)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(bill, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Check(err, Equals, f9mission.ErrVoteLocked)
+
+	tally, _ := m.Tally()
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 2})
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteAbort)
+	c.Check(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+}
+
+func (*TestSuite) TestVoteLock_AbortOnRetract(c *C) {
+	var err error
+
+	m, jeb, bill := newVoteLockMission(c, f9mission.VoteLockAbortOnRetract)
+
+	// retracting before blastoff is fine
+	_, err = m.UpdateVote(jeb, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	_, err = m.UpdateVote(jeb, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(bill, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	ready, err := m.UpdateVote(jeb, f9mission.VoteNo)
+	c.Check(err, IsNil)
+	c.Check(ready, Equals, false)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+}
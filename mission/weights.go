@@ -0,0 +1,96 @@
+package f9mission
+
+import "math"
+
+// DefaultWeight is the weight of a crew member's vote, if they don't have
+// one set in the Weights mission parameter.
+const DefaultWeight = 1.0
+
+// WeightedTally is the map used for the current voting result tally, where
+// each vote is counted using the weight of the crew member who cast it. The
+// key is the Vote kind.
+type WeightedTally map[Vote]float64
+
+// InterfaceWeights is the interface for missions where each crew member's
+// vote has a weight.
+type InterfaceWeights interface {
+	// Weight returns the weight of the crew member's vote.
+	Weight(hashedKey string) float64
+
+	// WeightedTally returns the tally of votes, weighted by the crew member
+	// who cast each one, and whether there are enough votes to proceed with
+	// the mission.
+	WeightedTally() (WeightedTally, bool)
+}
+
+func validateWeights(weights map[string]float64) error {
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return errWeightInvalid
+		}
+	}
+
+	return nil
+}
+
+// weight returns the weight of the crew member's vote.
+func (m *Mission) weight(hashedKey string) float64 {
+	if w, ok := m.weights[hashedKey]; ok {
+		return w
+	}
+
+	return DefaultWeight
+}
+
+// totalWeight returns the total weight of the crew voting in the current
+// stage, including the crew on the roster who haven't joined. The gngMu and
+// crewMu must be held by the caller.
+func (m *Mission) totalWeight() float64 {
+	var total float64
+
+	present, missing := m.electorate()
+
+	for _, hashedKey := range append(present, missing...) {
+		total += m.weight(hashedKey)
+	}
+
+	return total
+}
+
+// weightedTally returns the weighted tally of the votes cast by the crew
+// voting in the current stage. The gngMu and crewMu must be held by the
+// caller.
+func (m *Mission) weightedTally() WeightedTally {
+	tally := make(WeightedTally)
+
+	present, missing := m.electorate()
+
+	for _, hashedKey := range append(present, missing...) {
+		if vote, ok := m.gngResults[hashedKey]; ok {
+			tally[vote] += m.weight(hashedKey)
+		}
+	}
+
+	return tally
+}
+
+// Weight returns the weight of the crew member's vote. Crew members without a
+// weight in the Weights mission parameter have the DefaultWeight.
+func (m *Mission) Weight(hashedKey string) float64 { return m.weight(hashedKey) }
+
+// WeightedTally returns the tally of votes, weighted by the crew member who
+// cast each one, and whether there are enough votes to proceed with the
+// mission.
+func (m *Mission) WeightedTally() (WeightedTally, bool) {
+	if m.CurrentState() == StateReady {
+		return nil, false
+	}
+
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	return m.weightedTally(), m.isReady(m.tally())
+}
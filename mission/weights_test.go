@@ -0,0 +1,118 @@
+package f9mission_test
+
+import (
+	"math"
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestNewMission_Weights(c *C) {
+	var err error
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Weights: map[string]float64{"abc": -1}})
+	c.Check(err, ErrorMatches, "crew weights must be finite numbers that are not negative")
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Weights: map[string]float64{"abc": math.NaN()}})
+	c.Check(err, NotNil)
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{Weights: map[string]float64{"abc": 2}})
+	c.Assert(err, IsNil)
+	c.Check(m.Weight("abc"), Equals, 2.0)
+	c.Check(m.Weight("def"), Equals, f9mission.DefaultWeight)
+}
+
+func (*TestSuite) TestMission_WeightedQuorum(c *C) {
+	var ready bool
+
+	// keys "0", "1", "2", "3"
+	lead := "5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"
+	trainee := "6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGWeightedQuorum,
+		BlastoffingCooldown: time.Millisecond * 10,
+		Weights: map[string]float64{
+			lead:    2,
+			trainee: 0.5,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	keys := addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman", "Bob Kerman", "Valentina Kerman")
+
+	wt, ready := m.WeightedTally()
+	c.Check(wt, IsNil)
+	c.Check(ready, Equals, false)
+
+	c.Assert(m.Initiate(), IsNil)
+
+	// the total weight is 2 + 0.5 + 1 + 1 = 4.5, so more than 2.25 is needed
+	bob := keys[2]
+
+	ready, err = m.UpdateVote(trainee, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+
+	ready, err = m.UpdateVote(bob, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+
+	wt, ready = m.WeightedTally()
+	c.Check(wt, DeepEquals, f9mission.WeightedTally{f9mission.VoteYes: 1.5})
+	c.Check(ready, Equals, false)
+
+	// the unweighted tally still counts each vote as one
+	tally, _ := m.Tally()
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 2})
+
+	c.Check(m.CrewStatus()[lead].Weight, Equals, 2.0)
+	c.Check(m.CrewStatus()[bob].Weight, Equals, 1.0)
+
+	ready, err = m.UpdateVote(lead, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	wt, ready = m.WeightedTally()
+	c.Check(wt, DeepEquals, f9mission.WeightedTally{f9mission.VoteYes: 3.5})
+	c.Check(ready, Equals, true)
+}
+
+func (*TestSuite) TestMission_WeightedQuorum_RemoveCrew(c *C) {
+	var ready bool
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGWeightedQuorum,
+		BlastoffingCooldown: time.Millisecond * 10,
+	})
+	c.Assert(err, IsNil)
+
+	keys := addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman", "Bob Kerman")
+
+	c.Assert(m.Initiate(), IsNil)
+
+	//
+	// Test that the vote of a crew member who's removed isn't counted
+	//
+	ready, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+
+	_, err = m.RemoveCrew(keys[0])
+	c.Assert(err, IsNil)
+
+	// the total weight is now 2, so a single vote isn't more than half
+	ready, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, false)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	wt, ready := m.WeightedTally()
+	c.Check(wt, DeepEquals, f9mission.WeightedTally{f9mission.VoteYes: 1})
+	c.Check(ready, Equals, false)
+
+	_, ok := m.CrewStatus()[keys[0]]
+	c.Check(ok, Equals, false)
+}
@@ -0,0 +1,221 @@
+package f9mission
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrVotingNotOpen is the error returned from Initiate() and Recycle() if the
+// mission has a launch window and its voting hasn't opened yet.
+var ErrVotingNotOpen = errors.New("voting for the launch window has not opened yet")
+
+// ErrWindowClosed is the error returned from Initiate() and Recycle() if the
+// mission's launch window has closed.
+var ErrWindowClosed = errors.New("the launch window has closed")
+
+// LaunchWindow is the time range within which a mission can blast off. If a
+// mission has enough "Go" votes before the window opens, it holds until the
+// window opens and then blasts off, as long as it's still ready. If the window
+// closes before the blastoff begins, the mission is scrubbed (aborted).
+type LaunchWindow struct {
+	// VotingOpens is when the Go/No-Go can be initiated. If this is the zero
+	// time, it can be initiated at any time before the window closes.
+	VotingOpens time.Time
+
+	// Opens is the earliest time the blastoff can begin.
+	Opens time.Time
+
+	// Closes is the latest time the blastoff can begin.
+	Closes time.Time
+}
+
+// InterfaceLaunchWindow is the interface for missions with a launch window.
+type InterfaceLaunchWindow interface {
+	// LaunchWindow returns the mission's launch window, or nil if it
+	// doesn't have one.
+	LaunchWindow() *LaunchWindow
+
+	// Holding returns whether the mission has enough "Go" votes to proceed,
+	// but is waiting for its launch window to open, or to be released by
+	// LaunchAt().
+	Holding() bool
+}
+
+func validateWindow(w *LaunchWindow) error {
+	if w == nil {
+		return nil
+	}
+
+	if w.Opens.IsZero() || w.Closes.IsZero() {
+		return errors.New("the launch window must have an open and close time")
+	}
+
+	if !w.Closes.After(w.Opens) {
+		return errors.New("the launch window must close after it opens")
+	}
+
+	if w.VotingOpens.After(w.Opens) {
+		return errors.New("voting for the launch window must open before the window does")
+	}
+
+	return nil
+}
+
+// LaunchWindow returns the mission's launch window, or nil if it doesn't
+// have one.
+func (m *Mission) LaunchWindow() *LaunchWindow {
+	if m.window == nil {
+		return nil
+	}
+
+	w := *m.window
+
+	return &w
+}
+
+// checkWindow returns whether a Go/No-Go can be initiated now, per the
+// mission's launch window.
+func (m *Mission) checkWindow() error {
+	if m.window == nil {
+		return nil
+	}
+
+	now := m.clock.Now()
+
+	if now.Before(m.window.VotingOpens) {
+		return ErrVotingNotOpen
+	}
+
+	if !now.Before(m.window.Closes) {
+		return ErrWindowClosed
+	}
+
+	return nil
+}
+
+// startScrubTimer starts the timer that scrubs the mission if the launch
+// window closes before the blastoff begins.
+func (m *Mission) startScrubTimer() {
+	if m.window == nil {
+		return
+	}
+
+	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
+
+	stopTimer(&m.scrub)
+
+	m.scrub = m.clock.AfterFunc(m.window.Closes.Sub(m.clock.Now()), func() {
+		if m.closed() {
+			return
+		}
+
+		defer m.flushStateChanges()
+
+		m.gngMu.Lock()
+		defer m.gngMu.Unlock()
+
+		if m.CurrentState() == StateVoting {
+			m.abort()
+		}
+	})
+}
+
+// hold waits until the launch window opens, and then starts the blastoff if
+// the mission is still ready.
+func (m *Mission) hold() {
+	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
+
+	if m.holding != nil {
+		return
+	}
+
+	m.holding = m.clock.AfterFunc(m.window.Opens.Sub(m.clock.Now()), func() {
+		if m.closed() {
+			return
+		}
+
+		defer m.flushStateChanges()
+
+		m.gngMu.Lock()
+		defer m.gngMu.Unlock()
+
+		m.crewMu.Lock()
+		defer m.crewMu.Unlock()
+
+		m.timerMu.Lock()
+		m.holding = nil
+		m.timerMu.Unlock()
+
+		if m.CurrentState() == StateVoting {
+			m.proceed()
+		}
+	})
+}
+
+// Holding returns whether the mission has enough "Go" votes to proceed, but
+// is waiting for its launch window to open, or to be released by LaunchAt(),
+// before the blastoff begins.
+func (m *Mission) Holding() bool {
+	m.gngMu.Lock()
+	defer m.gngMu.Unlock()
+
+	m.crewMu.Lock()
+	defer m.crewMu.Unlock()
+
+	if m.CurrentState() != StateVoting || !m.isReady(m.tally()) {
+		return false
+	}
+
+	return m.held() || (m.window != nil && m.clock.Now().Before(m.window.Opens))
+}
+
+// proceed starts the blastoff if the mission is ready, and the launch window
+// and coordination allow it. If the mission has stages, only the last one
+// starts the blastoff. The gngMu and crewMu must be held by the caller.
+func (m *Mission) proceed() (bool, error) {
+	isReady := m.isReady(m.tally())
+
+	if m.CurrentState() == StateBlastoffing {
+		return isReady, nil
+	}
+
+	if !isReady {
+		m.setReady(false)
+		return false, nil
+	}
+
+	// move on to the next stage of the launch sequence, if there is one
+	if len(m.stages) > 0 && m.stage < len(m.stages)-1 {
+		m.advanceStage()
+		return m.proceed()
+	}
+
+	// a coordinated mission holds until it's released by LaunchAt()
+	if m.setReady(true); m.held() {
+		return true, nil
+	}
+
+	if m.window != nil {
+		now := m.clock.Now()
+
+		switch {
+		case now.Before(m.window.Opens):
+			m.hold()
+			return true, nil
+		case !now.Before(m.window.Closes):
+			return false, m.abort()
+		}
+	}
+
+	// the blastoff is beginning, so the mission isn't ready for another one
+	m.setReady(false)
+
+	err := m.transition(StateBlastoffing)
+
+	// set our status to StateFinished once the cooldown elapses
+	m.startCooldown()
+
+	return isReady, err
+}
@@ -0,0 +1,148 @@
+package f9mission_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	. "gopkg.in/check.v1"
+)
+
+var windowEpoch = time.Date(2016, time.January, 1, 19, 0, 0, 0, time.UTC)
+
+// newWindowMission returns a mission with two crew members whose voting
+// opens at 19:30 UTC, and whose launch window is from 20:00 to 20:10 UTC. The
+// clock starts at 19:00 UTC.
+func newWindowMission(c *C) (*f9mission.Mission, *f9missiontest.Clock, []string) {
+	clock := f9missiontest.NewClock(windowEpoch)
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{
+		GoNoGo:              f9mission.GNGAll,
+		BlastoffingCooldown: time.Second * 10,
+		Clock:               clock,
+		Window: &f9mission.LaunchWindow{
+			VotingOpens: windowEpoch.Add(time.Minute * 30),
+			Opens:       windowEpoch.Add(time.Hour),
+			Closes:      windowEpoch.Add(time.Hour + time.Minute*10),
+		},
+	})
+	c.Assert(err, IsNil)
+
+	return m, clock, addNamedCrew(m, c, "Jebediah Kerman", "Bill Kerman")
+}
+
+func (*TestSuite) TestNewMission_Window(c *C) {
+	var err error
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Window: &f9mission.LaunchWindow{Opens: windowEpoch}})
+	c.Check(err, ErrorMatches, "the launch window must have an open and close time")
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Window: &f9mission.LaunchWindow{Opens: windowEpoch, Closes: windowEpoch}})
+	c.Check(err, ErrorMatches, "the launch window must close after it opens")
+
+	_, err = f9mission.NewMission(&f9mission.MissionParams{Window: &f9mission.LaunchWindow{
+		VotingOpens: windowEpoch.Add(time.Minute),
+		Opens:       windowEpoch,
+		Closes:      windowEpoch.Add(time.Hour),
+	}})
+	c.Check(err, ErrorMatches, "voting for the launch window must open before the window does")
+
+	m, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+	c.Check(m.LaunchWindow(), IsNil)
+}
+
+func (*TestSuite) TestMission_WindowHold(c *C) {
+	var ready bool
+	var err error
+
+	m, clock, keys := newWindowMission(c)
+	c.Check(m.LaunchWindow().Opens, Equals, windowEpoch.Add(time.Hour))
+
+	//
+	// Test that the Go/No-Go can't start before voting opens
+	//
+	c.Check(m.Initiate(), Equals, f9mission.ErrVotingNotOpen)
+
+	clock.Advance(time.Minute * 30)
+	c.Assert(m.Initiate(), IsNil)
+
+	//
+	// Test that an early Go holds until the window opens
+	//
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.Holding(), Equals, false)
+
+	ready, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(ready, Equals, true)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(m.Holding(), Equals, true)
+
+	clock.Advance(time.Minute*30 - time.Second)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	clock.Advance(time.Second)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(m.Holding(), Equals, false)
+
+	//
+	// Test that the window closing doesn't scrub a blastoff in progress
+	//
+	clock.Advance(time.Second * 10)
+	c.Check(m.CurrentState(), Equals, f9mission.StateFinished)
+	c.Check(clock.Timers(), Equals, 0)
+}
+
+func (*TestSuite) TestMission_WindowHoldNoLongerReady(c *C) {
+	var err error
+
+	m, clock, keys := newWindowMission(c)
+
+	clock.Advance(time.Minute * 30)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	// a crew member changes their mind while holding
+	_, err = m.UpdateVote(keys[1], f9mission.VoteNo)
+	c.Assert(err, IsNil)
+	c.Check(m.Holding(), Equals, false)
+
+	clock.Advance(time.Minute * 30)
+	c.Check(m.CurrentState(), Equals, f9mission.StateVoting)
+
+	// once the window is open, a Go blasts off straight away
+	_, err = m.UpdateVote(keys[1], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+}
+
+func (*TestSuite) TestMission_WindowScrub(c *C) {
+	var err error
+
+	m, clock, keys := newWindowMission(c)
+
+	clock.Advance(time.Minute * 30)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(keys[0], f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	//
+	// Test that the mission is scrubbed when the window closes
+	//
+	clock.Advance(time.Minute * 40)
+	c.Check(m.CurrentState(), Equals, f9mission.StateAborted)
+	c.Check(clock.Timers(), Equals, 0)
+
+	c.Check(m.Initiate(), Equals, f9mission.ErrWindowClosed)
+
+	_, err = m.Recycle(true)
+	c.Check(err, Equals, f9mission.ErrWindowClosed)
+}
@@ -0,0 +1,135 @@
+package f9missioncontrol
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+)
+
+// DefaultChallengeTTL is how long a challenge is valid for, if a TTL isn't
+// provided to NewAuthenticator().
+const DefaultChallengeTTL = time.Second * 30
+
+// ChallengeSize is the size, in bytes, of the nonces issued by Challenge().
+const ChallengeSize = 32
+
+// ErrInvalidChallenge is the error returned from Verify() if the nonce wasn't
+// issued by the authenticator, has already been used, or has expired.
+var ErrInvalidChallenge = errors.New("the challenge is unknown, expired, or already used")
+
+// ErrInvalidSignature is the error returned from Verify() if the signature of
+// the challenge message doesn't match the crew member's public key.
+var ErrInvalidSignature = errors.New("the challenge signature is not valid")
+
+// Authenticator does challenge-response authentication of crew members. Each
+// crew member holds an Ed25519 key pair, and their identity is derived from
+// the public key. To authenticate, the crew member signs the ChallengeMessage()
+// of a nonce issued by Challenge() with their private key. Each nonce can only
+// be used once, so a captured signature can't be replayed, and the message
+// includes the crew member's name and mission ID, so a signature can't be used
+// to join under another name or to join another mission.
+//
+// This struct should be created by using the NewAuthenticator() function.
+type Authenticator struct {
+	ttl        time.Duration
+	clock      f9mission.Clock
+	challenges map[string]time.Time
+	mu         sync.Mutex
+}
+
+// NewAuthenticator returns an Authenticator whose challenges expire after
+// ttl. If ttl is zero, DefaultChallengeTTL is used.
+func NewAuthenticator(ttl time.Duration) *Authenticator {
+	return NewAuthenticatorWithClock(ttl, f9mission.SystemClock)
+}
+
+// NewAuthenticatorWithClock is the same as NewAuthenticator(), except that
+// the expiry of challenges uses the Clock provided. If clock is nil,
+// f9mission.SystemClock is used.
+func NewAuthenticatorWithClock(ttl time.Duration, clock f9mission.Clock) *Authenticator {
+	if ttl == 0 {
+		ttl = DefaultChallengeTTL
+	}
+
+	if clock == nil {
+		clock = f9mission.SystemClock
+	}
+
+	return &Authenticator{
+		ttl:        ttl,
+		clock:      clock,
+		challenges: make(map[string]time.Time),
+	}
+}
+
+// Challenge issues a new nonce for a crew member to sign.
+func (a *Authenticator) Challenge() ([]byte, error) {
+	nonce := make([]byte, ChallengeSize)
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+
+	// purge any expired challenges that were never used
+	for k, expires := range a.challenges {
+		if now.After(expires) {
+			delete(a.challenges, k)
+		}
+	}
+
+	a.challenges[hex.EncodeToString(nonce)] = now.Add(a.ttl)
+
+	return nonce, nil
+}
+
+// ChallengeMessage returns the message a crew member signs to answer the
+// challenge nonce: the nonce, followed by their name, followed by the ID of
+// the mission they're joining as a big-endian uint32.
+func ChallengeMessage(nonce []byte, name string, missionID uint32) []byte {
+	msg := make([]byte, len(nonce)+len(name)+4)
+
+	n := copy(msg, nonce)
+	n += copy(msg[n:], name)
+
+	binary.BigEndian.PutUint32(msg[n:], missionID)
+
+	return msg
+}
+
+// Verify checks that sig is a signature of the ChallengeMessage() made by the
+// private key matching pub, and that nonce was issued by this authenticator
+// and hasn't expired. The nonce is consumed whether or not the signature is
+// valid. If the crew member is authenticated, this returns the crew member
+// identified by the public key, whose HashedKey is made using hasher.
+func (a *Authenticator) Verify(name string, pub ed25519.PublicKey, missionID uint32, nonce, sig []byte, hasher f9crew.Hasher) (*f9crew.CrewMember, error) {
+	key := hex.EncodeToString(nonce)
+
+	a.mu.Lock()
+
+	expires, ok := a.challenges[key]
+	delete(a.challenges, key)
+
+	a.mu.Unlock()
+
+	if !ok || a.clock.Now().After(expires) {
+		return nil, ErrInvalidChallenge
+	}
+
+	if len(pub) != ed25519.PublicKeySize || !ed25519.Verify(pub, ChallengeMessage(nonce, name, missionID), sig) {
+		return nil, ErrInvalidSignature
+	}
+
+	return f9crew.NewCrewMemberFromPublicKey(name, pub, hasher)
+}
@@ -0,0 +1,229 @@
+package f9missioncontrol_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+func genKey(c *C) (ed25519.PublicKey, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, IsNil)
+	return pub, priv
+}
+
+func (*TestSuite) TestAuthenticator_Verify(c *C) {
+	var cm *f9crew.CrewMember
+	var err error
+
+	auth := f9missioncontrol.NewAuthenticator(0)
+	pub, priv := genKey(c)
+	otherPub, _ := genKey(c)
+
+	// sign signs the challenge message of Jebediah joining mission 1
+	sign := func(nonce []byte) []byte {
+		return ed25519.Sign(priv, f9missioncontrol.ChallengeMessage(nonce, "Jebediah Kerman", 1))
+	}
+
+	nonce, err := auth.Challenge()
+	c.Assert(err, IsNil)
+	c.Assert(len(nonce), Equals, f9missioncontrol.ChallengeSize)
+
+	sig := sign(nonce)
+
+	//
+	// Test that a signature from a different key is rejected, and that
+	// the failed attempt consumes the nonce
+	//
+	cm, err = auth.Verify("Jebediah Kerman", otherPub, 1, nonce, sig, f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidSignature)
+	c.Check(cm, IsNil)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sig, f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidChallenge)
+	c.Check(cm, IsNil)
+
+	//
+	// Test that a valid signature authenticates the crew member
+	//
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	sig = sign(nonce)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sig, f9crew.SHA256Hasher)
+	c.Assert(err, IsNil)
+	c.Assert(cm, NotNil)
+	c.Check(cm.Name(), Equals, "Jebediah Kerman")
+	c.Check(cm.HashedKey(), Equals, f9crew.HashKey(string(pub)))
+
+	//
+	// Test that the signature can't be replayed
+	//
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sig, f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidChallenge)
+	c.Check(cm, IsNil)
+
+	//
+	// Test that a nonce the authenticator never issued is rejected
+	//
+	nonce = make([]byte, f9missioncontrol.ChallengeSize)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidChallenge)
+	c.Check(cm, IsNil)
+
+	//
+	// Test that a malformed public key is rejected
+	//
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub[:8], 1, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidSignature)
+	c.Check(cm, IsNil)
+
+	//
+	// Test that the signature can't be used for another name or mission
+	//
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	cm, err = auth.Verify("Bill Kerman", pub, 1, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidSignature)
+	c.Check(cm, IsNil)
+
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 2, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidSignature)
+	c.Check(cm, IsNil)
+
+	//
+	// Test that the crew member's HashedKey is made using the hasher
+	//
+	hasher, err := f9crew.NewHMACHasher([]byte("secret"))
+	c.Assert(err, IsNil)
+
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sign(nonce), hasher)
+	c.Assert(err, IsNil)
+	c.Check(cm.HashedKey(), Equals, hasher.Hash(string(pub)))
+}
+
+func (*TestSuite) TestAuthenticator_Expiry(c *C) {
+	clock := f9missiontest.NewClock(scheduleEpoch)
+	auth := f9missioncontrol.NewAuthenticatorWithClock(time.Millisecond*10, clock)
+	pub, priv := genKey(c)
+
+	sign := func(nonce []byte) []byte {
+		return ed25519.Sign(priv, f9missioncontrol.ChallengeMessage(nonce, "Jebediah Kerman", 1))
+	}
+
+	//
+	// Test that a challenge can be used until it expires
+	//
+	nonce, err := auth.Challenge()
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Millisecond * 10)
+
+	cm, err := auth.Verify("Jebediah Kerman", pub, 1, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, IsNil)
+	c.Check(cm, NotNil)
+
+	//
+	// Test that an expired challenge is rejected
+	//
+	nonce, err = auth.Challenge()
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Millisecond * 11)
+
+	cm, err = auth.Verify("Jebediah Kerman", pub, 1, nonce, sign(nonce), f9crew.SHA256Hasher)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidChallenge)
+	c.Check(cm, IsNil)
+}
+
+func (*TestSuite) TestMissionControl_Join(c *C) {
+	var ifc f9crew.Interface
+	var err error
+
+	mission, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+
+	mc := &f9missioncontrol.MissionControl{Mission: mission}
+	pub, priv := genKey(c)
+
+	//
+	// Test that joining requires an authenticator
+	//
+	ifc, err = mc.Join(&f9missioncontrol.JoinRequest{Name: "Jebediah Kerman"})
+	c.Check(err, Equals, f9missioncontrol.ErrNoAuthenticator)
+	c.Check(ifc, IsNil)
+
+	mc.Auth = f9missioncontrol.NewAuthenticator(0)
+
+	ifc, err = mc.Join(nil)
+	c.Check(err, ErrorMatches, "join request cannot be nil")
+	c.Check(ifc, IsNil)
+
+	//
+	// Test that an unsigned request doesn't join the mission
+	//
+	nonce, err := mc.Auth.Challenge()
+	c.Assert(err, IsNil)
+
+	ifc, err = mc.Join(&f9missioncontrol.JoinRequest{
+		Name:      "Jebediah Kerman",
+		PublicKey: pub,
+		Nonce:     nonce,
+	})
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidSignature)
+	c.Check(ifc, IsNil)
+	c.Check(len(mission.Crew()), Equals, 0)
+
+	//
+	// Test that a signed request joins the mission
+	//
+	nonce, err = mc.Auth.Challenge()
+	c.Assert(err, IsNil)
+
+	ifc, err = mc.Join(&f9missioncontrol.JoinRequest{
+		Name:      "Jebediah Kerman",
+		PublicKey: pub,
+		Nonce:     nonce,
+		Signature: ed25519.Sign(priv, f9missioncontrol.ChallengeMessage(nonce, "Jebediah Kerman", mission.ID())),
+	})
+	c.Assert(err, IsNil)
+	c.Check(ifc.HashedKey(), Equals, f9crew.HashKey(string(pub)))
+
+	crew := mission.Crew()
+	c.Assert(len(crew), Equals, 1)
+	c.Check(crew[0].HashedKey(), Equals, ifc.HashedKey())
+
+	//
+	// Test that errors from the mission are returned
+	//
+	nonce, err = mc.Auth.Challenge()
+	c.Assert(err, IsNil)
+
+	ifc, err = mc.Join(&f9missioncontrol.JoinRequest{
+		Name:      "Jebediah Kerman",
+		PublicKey: pub,
+		Nonce:     nonce,
+		Signature: ed25519.Sign(priv, f9missioncontrol.ChallengeMessage(nonce, "Jebediah Kerman", mission.ID())),
+	})
+	c.Check(err, Equals, f9mission.ErrCrewMemberAlreadyPresent)
+	c.Check(ifc, IsNil)
+}
@@ -0,0 +1,177 @@
+package f9missioncontrol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSpec is a parsed cron-like schedule. It has five space-separated fields:
+// minute (0-59), hour (0-23), day of month (1-31), month (1-12), and day of
+// week (0-6, where 0 is Sunday). Each field is either "*", or a
+// comma-separated list of values and ranges (e.g., "1-5"), each of which may
+// have a step (e.g., "*/15" or "0-30/10").
+//
+// As with cron, if both the day of month and day of week are restricted, a
+// time matches when either of them does.
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64
+
+	domStar, dowStar bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// ParseCron parses a cron-like schedule. The descriptors @yearly, @monthly,
+// @weekly, @daily and @hourly are also supported.
+func ParseCron(spec string) (*CronSpec, error) {
+	if d, ok := cronDescriptors[strings.TrimSpace(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron spec %q must have %d fields", spec, len(cronFields))
+	}
+
+	var bits [5]uint64
+
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		bits[i] = b
+	}
+
+	return &CronSpec{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(s string, field cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+
+			rng = part[:i]
+
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in cron %s field %q", field.name, s)
+			}
+		}
+
+		lo, hi := field.min, field.max
+
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+
+			var err error
+
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in cron %s field %q", field.name, s)
+			}
+
+			hi = lo
+
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value in cron %s field %q", field.name, s)
+				}
+			} else if step > 1 {
+				// "5/15" is the same as "5-max/15"
+				hi = field.max
+			}
+		}
+
+		if lo < field.min || hi > field.max || lo > hi {
+			return 0, fmt.Errorf("cron %s field %q is outside of %d-%d", field.name, s, field.min, field.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (c *CronSpec) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// Next returns the first time after t that matches the schedule, in t's
+// location. If nothing matches within five years (e.g., "0 0 31 2 *") this
+// returns the zero time.
+func (c *CronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,58 @@
+package f9missioncontrol_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*TestSuite) TestParseCron(c *C) {
+	var err error
+
+	_, err = f9missioncontrol.ParseCron("* * * *")
+	c.Check(err, ErrorMatches, `cron spec "\* \* \* \*" must have 5 fields`)
+
+	_, err = f9missioncontrol.ParseCron("60 * * * *")
+	c.Check(err, ErrorMatches, `cron minute field "60" is outside of 0-59`)
+
+	_, err = f9missioncontrol.ParseCron("* * 0 * *")
+	c.Check(err, ErrorMatches, `cron day of month field "0" is outside of 1-31`)
+
+	_, err = f9missioncontrol.ParseCron("*/0 * * * *")
+	c.Check(err, ErrorMatches, `invalid step in cron minute field "\*/0"`)
+
+	_, err = f9missioncontrol.ParseCron("a * * * *")
+	c.Check(err, ErrorMatches, `invalid value in cron minute field "a"`)
+
+	_, err = f9missioncontrol.ParseCron("@daily")
+	c.Check(err, IsNil)
+}
+
+func (*TestSuite) TestCronSpec_Next(c *C) {
+	// a Friday
+	now := time.Date(2016, time.January, 1, 8, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2016, time.January, 1, 8, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2016, time.January, 1, 8, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2016, time.January, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2016, time.January, 4, 8, 0, 0, 0, time.UTC)},
+		{"0,30 8 * * *", time.Date(2016, time.January, 2, 8, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2016, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2016, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		// the day of month or the day of week can match
+		{"0 0 15 * 0", time.Date(2016, time.January, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		spec, err := f9missioncontrol.ParseCron(tt.spec)
+		c.Assert(err, IsNil)
+		c.Check(spec.Next(now), Equals, tt.next, Commentf("spec: %s", tt.spec))
+	}
+}
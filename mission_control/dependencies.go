@@ -0,0 +1,270 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/theckman/falcon9/mission"
+)
+
+// ErrDependencyCycle is the error returned from AddDependency() if the
+// dependency would make a mission depend on itself.
+var ErrDependencyCycle = errors.New("the dependency would create a cycle")
+
+// ErrUpstreamNotFinished is the error returned when initiating a mission
+// whose upstream missions haven't all finished.
+var ErrUpstreamNotFinished = errors.New("the mission's upstream missions have not all finished")
+
+type missionDependencies struct {
+	// upstream is the missions each mission depends on, keyed by mission ID.
+	upstream map[uint32]map[uint32]struct{}
+
+	// auto is the missions that are initiated when their upstream missions
+	// have all finished.
+	auto map[uint32]bool
+
+	// watches is the function to stop watching the state of each mission.
+	watches map[uint32]func()
+}
+
+// AddDependency makes the mission with the ID depend on the upstream mission.
+// The mission can't be initiated by MissionControl.Initiate() or a Scheduler
+// until all of its upstream missions are in f9mission.StateFinished, and if an
+// upstream mission aborts the mission is aborted too, if it implements
+// f9mission.InterfaceAbort. Both missions must be in the registry. If the
+// dependency would create a cycle, this will return a ErrDependencyCycle
+// error.
+func AddDependency(id, upstream uint32) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, mid := range []uint32{id, upstream} {
+		if _, ok := registry.missions[mid]; !ok {
+			return fmt.Errorf("Mission with ID %d is not registered", mid)
+		}
+	}
+
+	if id == upstream || dependsOn(upstream, id) {
+		return ErrDependencyCycle
+	}
+
+	if registry.deps.upstream[id] == nil {
+		registry.deps.upstream[id] = make(map[uint32]struct{})
+	}
+
+	registry.deps.upstream[id][upstream] = struct{}{}
+
+	return nil
+}
+
+// dependsOn returns whether the mission with the ID depends on the target,
+// directly or through its upstream missions. The registryMu must be held by
+// the caller.
+func dependsOn(id, target uint32) bool {
+	seen := make(map[uint32]struct{})
+	queue := []uint32{id}
+
+	for len(queue) > 0 {
+		mid := queue[0]
+		queue = queue[1:]
+
+		for up := range registry.deps.upstream[mid] {
+			if up == target {
+				return true
+			}
+
+			if _, ok := seen[up]; !ok {
+				seen[up] = struct{}{}
+				queue = append(queue, up)
+			}
+		}
+	}
+
+	return false
+}
+
+// RemoveDependency removes a dependency added by AddDependency(). Removing a
+// dependency that doesn't exist does nothing.
+func RemoveDependency(id, upstream uint32) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry.deps.upstream[id], upstream)
+
+	if len(registry.deps.upstream[id]) == 0 {
+		delete(registry.deps.upstream, id)
+	}
+}
+
+// Upstream returns the IDs of the missions that the mission with the ID
+// directly depends on, in ascending order.
+func Upstream(id uint32) []uint32 {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return sortedIDs(registry.deps.upstream[id])
+}
+
+// Downstream returns the IDs of the missions that directly depend on the
+// mission with the ID, in ascending order.
+func Downstream(id uint32) []uint32 {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return downstream(id)
+}
+
+// downstream returns the IDs of the missions that directly depend on the
+// mission with the ID. The registryMu must be held by the caller.
+func downstream(id uint32) []uint32 {
+	ids := make(map[uint32]struct{})
+
+	for mid, ups := range registry.deps.upstream {
+		if _, ok := ups[id]; ok {
+			ids[mid] = struct{}{}
+		}
+	}
+
+	return sortedIDs(ids)
+}
+
+func sortedIDs(set map[uint32]struct{}) []uint32 {
+	ids := make([]uint32, 0, len(set))
+
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// SetAutoInitiate sets whether the mission with the ID is initiated
+// automatically once all of its upstream missions have finished. Errors from
+// initiating it, such as it having no crew, are ignored.
+func SetAutoInitiate(id uint32, auto bool) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry.missions[id]; !ok {
+		return fmt.Errorf("Mission with ID %d is not registered", id)
+	}
+
+	if auto {
+		registry.deps.auto[id] = true
+	} else {
+		delete(registry.deps.auto, id)
+	}
+
+	return nil
+}
+
+// upstreamFinished returns whether all of the mission's upstream missions are
+// in StateFinished. The registryMu must be held by the caller.
+func upstreamFinished(id uint32) bool {
+	for up := range registry.deps.upstream[id] {
+		mc, ok := registry.missions[up]
+
+		if !ok || mc.Mission.CurrentState() != f9mission.StateFinished {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkUpstream returns a ErrUpstreamNotFinished error if the mission with the
+// ID has upstream missions that haven't finished.
+func checkUpstream(id uint32) error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if !upstreamFinished(id) {
+		return ErrUpstreamNotFinished
+	}
+
+	return nil
+}
+
+// initiateMission initiates the mission with the ID, if its upstream missions
+// have all finished. Everything in the package that initiates a mission uses
+// this, so that the mission's dependencies are always enforced.
+func initiateMission(id uint32, mc *MissionControl) error {
+	if err := checkUpstream(id); err != nil {
+		return err
+	}
+
+	return mc.Mission.Initiate()
+}
+
+// watchMission starts watching the state of the mission, to initiate and
+// abort its downstream missions. Missions that don't implement
+// f9mission.InterfaceStateChanges aren't watched. The registryMu must be held
+// by the caller.
+func watchMission(id uint32, mc *MissionControl) {
+	sc, ok := mc.Mission.(f9mission.InterfaceStateChanges)
+
+	if !ok {
+		return
+	}
+
+	registry.deps.watches[id] = sc.WatchState(func(change f9mission.StateChange) {
+		missionStateChanged(id, change)
+	})
+}
+
+// unwatchMission stops watching the state of the mission, and removes its
+// dependencies. The registryMu must be held by the caller.
+func unwatchMission(id uint32) {
+	if stop, ok := registry.deps.watches[id]; ok {
+		stop()
+		delete(registry.deps.watches, id)
+	}
+
+	delete(registry.deps.upstream, id)
+	delete(registry.deps.auto, id)
+
+	for mid, ups := range registry.deps.upstream {
+		delete(ups, id)
+
+		if len(ups) == 0 {
+			delete(registry.deps.upstream, mid)
+		}
+	}
+}
+
+// missionStateChanged propagates the state change of a mission to its
+// downstream missions: they're aborted if it aborts, and initiated if it
+// finishes and they're set to auto-initiate.
+func missionStateChanged(id uint32, change f9mission.StateChange) {
+	var abort []*MissionControl
+	initiate := make(map[uint32]*MissionControl)
+
+	registryMu.RLock()
+
+	for _, mid := range downstream(id) {
+		mc := registry.missions[mid]
+
+		switch {
+		case change.To == f9mission.StateAborted:
+			abort = append(abort, mc)
+		case change.To == f9mission.StateFinished && registry.deps.auto[mid] && upstreamFinished(mid):
+			initiate[mid] = mc
+		}
+	}
+
+	registryMu.RUnlock()
+
+	// the downstream missions' own state changes carry this on down the chain
+	for _, mc := range abort {
+		if a, ok := mc.Mission.(f9mission.InterfaceAbort); ok {
+			a.Abort()
+		}
+	}
+
+	for mid, mc := range initiate {
+		initiateMission(mid, mc)
+	}
+}
@@ -0,0 +1,221 @@
+package f9missioncontrol_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+// addChainMission adds a mission with one crew member to the registry, and
+// returns it along with the crew member's HashedKey.
+func addChainMission(c *C, id uint32, clock f9mission.Clock) (*f9missioncontrol.MissionControl, string) {
+	mission, err := f9mission.NewMission(&f9mission.MissionParams{
+		ID:                  id,
+		Clock:               clock,
+		BlastoffingCooldown: time.Second,
+	})
+	c.Assert(err, IsNil)
+
+	crew, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(mission.AddCrew(crew, false), IsNil)
+
+	mc := &f9missioncontrol.MissionControl{Mission: mission, Owner: testOwner}
+	c.Assert(f9missioncontrol.AddMission(id, mc), IsNil)
+
+	return mc, crew.HashedKey()
+}
+
+func (*TestSuite) TestAddDependency(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	id := randUint32()
+
+	addChainMission(c, id, nil)
+	addChainMission(c, id+1, nil)
+	addChainMission(c, id+2, nil)
+
+	c.Check(f9missioncontrol.AddDependency(id, id+3), ErrorMatches, "Mission with ID [0-9]+ is not registered")
+	c.Check(f9missioncontrol.AddDependency(id, id), Equals, f9missioncontrol.ErrDependencyCycle)
+
+	//
+	// Test that cycles are detected through the chain
+	//
+	c.Assert(f9missioncontrol.AddDependency(id+1, id), IsNil)
+	c.Assert(f9missioncontrol.AddDependency(id+2, id+1), IsNil)
+	c.Check(f9missioncontrol.AddDependency(id, id+2), Equals, f9missioncontrol.ErrDependencyCycle)
+
+	c.Check(f9missioncontrol.Upstream(id+2), DeepEquals, []uint32{id + 1})
+	c.Check(f9missioncontrol.Downstream(id), DeepEquals, []uint32{id + 1})
+
+	f9missioncontrol.RemoveDependency(id+2, id+1)
+	c.Check(f9missioncontrol.Upstream(id+2), DeepEquals, []uint32{})
+	c.Check(f9missioncontrol.AddDependency(id, id+2), IsNil)
+
+	//
+	// Test that removing a mission removes its dependencies
+	//
+	c.Check(f9missioncontrol.RemoveMission(id+1), NotNil)
+	c.Check(f9missioncontrol.Downstream(id), DeepEquals, []uint32{})
+	c.Check(f9missioncontrol.Upstream(id), DeepEquals, []uint32{id + 2})
+}
+
+func (*TestSuite) TestDependencies_Initiate(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(time.Now())
+	id := randUint32()
+
+	up, upKey := addChainMission(c, id, clock)
+	down, downKey := addChainMission(c, id+1, clock)
+
+	c.Assert(f9missioncontrol.AddDependency(id+1, id), IsNil)
+
+	//
+	// Test that the downstream mission can't start until upstream finishes
+	//
+	c.Check(down.Initiate(testOwner), Equals, f9missioncontrol.ErrUpstreamNotFinished)
+
+	c.Assert(up.Initiate(testOwner), IsNil)
+
+	_, err = up.Mission.UpdateVote(upKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second)
+	c.Assert(up.Mission.CurrentState(), Equals, f9mission.StateFinished)
+
+	// it isn't initiated automatically unless asked to be
+	c.Check(down.Mission.CurrentState(), Equals, f9mission.StateReady)
+	c.Check(down.Initiate(testOwner), IsNil)
+
+	//
+	// Test that an upstream abort propagates downstream
+	//
+	c.Assert(up.Initiate(testOwner), IsNil)
+	c.Assert(up.Mission.(*f9mission.Mission).Abort(), IsNil)
+	c.Check(down.Mission.CurrentState(), Equals, f9mission.StateAborted)
+
+	_, err = down.Mission.UpdateVote(downKey, f9mission.VoteYes)
+	c.Check(err, Equals, f9mission.ErrVotingNotInProgress)
+}
+
+// abortOnlyMission only implements f9mission.Interface and
+// f9mission.InterfaceAbort, like a consumer's own mission implementation.
+type abortOnlyMission struct {
+	baseMission
+}
+
+func (m abortOnlyMission) Abort() error { return m.Interface.(*f9mission.Mission).Abort() }
+
+func (*TestSuite) TestDependencies_AbortOnly(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(time.Now())
+	id := randUint32()
+
+	up, _ := addChainMission(c, id, clock)
+	down, _ := addChainMission(c, id+1, clock)
+
+	down.Mission = abortOnlyMission{baseMission{down.Mission}}
+
+	c.Assert(f9missioncontrol.AddDependency(id+1, id), IsNil)
+
+	//
+	// Test that a mission which only implements InterfaceAbort is still
+	// aborted when its upstream mission aborts
+	//
+	c.Assert(up.Mission.Initiate(), IsNil)
+	c.Assert(down.Mission.Initiate(), IsNil)
+
+	c.Assert(up.Mission.(*f9mission.Mission).Abort(), IsNil)
+	c.Check(down.Mission.CurrentState(), Equals, f9mission.StateAborted)
+}
+
+func (*TestSuite) TestDependencies_Chain(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(time.Now())
+	id := randUint32()
+
+	first, firstKey := addChainMission(c, id, clock)
+	second, secondKey := addChainMission(c, id+1, clock)
+	third, _ := addChainMission(c, id+2, clock)
+
+	c.Assert(f9missioncontrol.AddDependency(id+1, id), IsNil)
+	c.Assert(f9missioncontrol.AddDependency(id+2, id+1), IsNil)
+
+	c.Check(f9missioncontrol.SetAutoInitiate(id+3, true), NotNil)
+	c.Assert(f9missioncontrol.SetAutoInitiate(id+1, true), IsNil)
+	c.Assert(f9missioncontrol.SetAutoInitiate(id+2, true), IsNil)
+
+	//
+	// Test that finishing a mission initiates the next one in the chain
+	//
+	c.Assert(first.Initiate(testOwner), IsNil)
+
+	_, err = first.Mission.UpdateVote(firstKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second)
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(third.Mission.CurrentState(), Equals, f9mission.StateReady)
+
+	_, err = second.Mission.UpdateVote(secondKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second)
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateFinished)
+	c.Check(third.Mission.CurrentState(), Equals, f9mission.StateVoting)
+
+	//
+	// Test that aborts propagate down the whole chain
+	//
+	c.Assert(first.Initiate(testOwner), IsNil)
+	c.Assert(second.Mission.Initiate(), IsNil)
+	c.Assert(first.Mission.(*f9mission.Mission).Abort(), IsNil)
+
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateAborted)
+	c.Check(third.Mission.CurrentState(), Equals, f9mission.StateAborted)
+}
+
+func (*TestSuite) TestDependencies_Scheduler(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(scheduleEpoch)
+	id := randUint32()
+
+	addChainMission(c, id, clock)
+	down, _ := addChainMission(c, id+1, clock)
+
+	c.Assert(f9missioncontrol.AddDependency(id+1, id), IsNil)
+
+	s := f9missioncontrol.NewScheduler(clock)
+	defer s.Stop()
+
+	c.Assert(s.Add(&f9missioncontrol.Schedule{Name: "deploy", Spec: "@hourly", Params: f9mission.MissionParams{ID: id + 1}}), IsNil)
+
+	//
+	// Test that a scheduled run doesn't initiate the mission before its
+	// upstream missions finish
+	//
+	clock.Advance(time.Hour)
+
+	status, err := s.Get("deploy")
+	c.Assert(err, IsNil)
+	c.Check(status.LastErr, Equals, f9missioncontrol.ErrUpstreamNotFinished)
+	c.Check(down.Mission.CurrentState(), Equals, f9mission.StateReady)
+}
@@ -0,0 +1,359 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+)
+
+// DefaultGroupCountdown is the Countdown of a MissionGroup if one isn't set.
+const DefaultGroupCountdown = time.Second * 10
+
+// MissionGroup is a group of missions that blast off together, such as one
+// mission per team. Each mission in the group holds once it's ready, and
+// when all of them are ready they're given the same launch time, Countdown
+// from then. If any of them stops being ready before the launch time, the
+// launch is cancelled and they hold until they're all ready again.
+type MissionGroup struct {
+	// Name is the unique name of the group.
+	Name string
+
+	// Missions is the ID of each mission in the group. They must be in the
+	// registry, and can't be in another group.
+	Missions []uint32
+
+	// Countdown is how long after all of the missions are ready that they
+	// blast off. If unset, this defaults to DefaultGroupCountdown.
+	Countdown time.Duration
+
+	// Clock is used to work out the launch time. If unset, this defaults to
+	// f9mission.SystemClock.
+	Clock f9mission.Clock
+}
+
+// GroupStatus is the status of a MissionGroup.
+type GroupStatus struct {
+	Name     string
+	Missions []uint32
+
+	// Ready is the IDs of the missions that are ready, and holding for the
+	// rest of the group, in ascending order.
+	Ready []uint32
+
+	// LaunchTime is when the missions blast off. It's the zero time if they
+	// aren't all ready.
+	LaunchTime time.Time
+}
+
+type missionGroup struct {
+	group   MissionGroup
+	members map[uint32]f9mission.InterfaceCoordination
+	stops   map[uint32]func()
+	ready   map[uint32]struct{}
+	launch  time.Time
+	started bool
+	removed bool
+	mu      sync.Mutex
+}
+
+// AddGroup adds a mission group to the registry, and starts coordinating the
+// blastoff of its missions. This returns an error if the group has no name or
+// missions, one of its missions isn't registered, is already in a group, or
+// doesn't implement f9mission.InterfaceCoordination, or the registry already
+// has a group with that name.
+func AddGroup(group *MissionGroup) error {
+	if group == nil || group.Name == "" {
+		return errors.New("the mission group must have a name")
+	}
+
+	if len(group.Missions) == 0 {
+		return errors.New("the mission group must have missions")
+	}
+
+	if group.Countdown < 0 {
+		return errors.New("the mission group's countdown cannot be negative")
+	}
+
+	g := &missionGroup{
+		group:   *group,
+		members: make(map[uint32]f9mission.InterfaceCoordination, len(group.Missions)),
+		stops:   make(map[uint32]func(), len(group.Missions)),
+		ready:   make(map[uint32]struct{}),
+	}
+
+	g.group.Missions = append([]uint32(nil), group.Missions...)
+
+	if g.group.Countdown == 0 {
+		g.group.Countdown = DefaultGroupCountdown
+	}
+
+	if g.group.Clock == nil {
+		g.group.Clock = f9mission.SystemClock
+	}
+
+	registryMu.Lock()
+
+	if err := registerGroup(g); err != nil {
+		registryMu.Unlock()
+		return err
+	}
+
+	members := make(map[uint32]f9mission.InterfaceCoordination, len(g.members))
+
+	for id, mission := range g.members {
+		members[id] = mission
+	}
+
+	registryMu.Unlock()
+
+	// this is done without holding the locks, as a mission that's already
+	// ready reports it before Coordinate() returns
+	for id, mission := range members {
+		id := id
+
+		stop := mission.Coordinate(func(ready bool) { g.readyChanged(id, ready) })
+
+		g.mu.Lock()
+
+		if _, ok := g.members[id]; ok && !g.removed {
+			g.stops[id] = stop
+			stop = nil
+		}
+
+		g.mu.Unlock()
+
+		// the mission or group was removed in the meantime
+		if stop != nil {
+			stop()
+		}
+	}
+
+	// the launch is only set once all of the missions are coordinated
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.started = true
+	g.check()
+
+	return nil
+}
+
+// registerGroup checks the group's missions, and adds it to the registry. The
+// registryMu must be held by the caller.
+func registerGroup(g *missionGroup) error {
+	if _, ok := registry.groups[g.group.Name]; ok {
+		return fmt.Errorf("Mission group with name %q already registered", g.group.Name)
+	}
+
+	for _, id := range g.group.Missions {
+		mc, ok := registry.missions[id]
+
+		if !ok || mc.Mission == nil {
+			return fmt.Errorf("Mission with ID %d is not registered", id)
+		}
+
+		coord, ok := mc.Mission.(f9mission.InterfaceCoordination)
+
+		if !ok {
+			return fmt.Errorf("Mission with ID %d does not support coordinated launches", id)
+		}
+
+		if name, ok := registry.grouped[id]; ok {
+			return fmt.Errorf("Mission with ID %d is already in group %q", id, name)
+		}
+
+		if _, ok := g.members[id]; ok {
+			return fmt.Errorf("Mission with ID %d is in the group more than once", id)
+		}
+
+		g.members[id] = coord
+	}
+
+	registry.groups[g.group.Name] = g
+
+	for _, id := range g.group.Missions {
+		registry.grouped[id] = g.group.Name
+	}
+
+	return nil
+}
+
+// readyChanged records whether the mission in the group is ready, and sets or
+// cancels the group's launch.
+func (g *missionGroup) readyChanged(id uint32, ready bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.members[id]; !ok || g.removed {
+		return
+	}
+
+	// once the launch time has passed the missions are blasting off, and
+	// any that weren't ready stay behind, so the next launch starts afresh
+	if !g.launch.IsZero() && !g.group.Clock.Now().Before(g.launch) {
+		g.launch = time.Time{}
+	}
+
+	if !ready {
+		delete(g.ready, id)
+
+		if !g.launch.IsZero() {
+			g.launch = time.Time{}
+			g.launchAll()
+		}
+
+		return
+	}
+
+	g.ready[id] = struct{}{}
+	g.check()
+}
+
+// check sets the group's launch time if all of its missions are ready. The mu
+// must be held by the caller.
+func (g *missionGroup) check() {
+	if !g.started || g.removed || !g.launch.IsZero() || len(g.members) == 0 || len(g.ready) < len(g.members) {
+		return
+	}
+
+	g.launch = g.group.Clock.Now().Add(g.group.Countdown)
+	g.launchAll()
+}
+
+// launchAll gives each of the group's missions its launch time, or cancels it
+// if the launch time is zero. The mu must be held by the caller.
+func (g *missionGroup) launchAll() {
+	for _, mission := range g.members {
+		mission.LaunchAt(g.launch)
+	}
+}
+
+// status returns the status of the group.
+func (g *missionGroup) status() *GroupStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ready := make(map[uint32]struct{}, len(g.ready))
+
+	for id := range g.ready {
+		ready[id] = struct{}{}
+	}
+
+	return &GroupStatus{
+		Name:       g.group.Name,
+		Missions:   append([]uint32(nil), g.group.Missions...),
+		Ready:      sortedIDs(ready),
+		LaunchTime: g.launch,
+	}
+}
+
+// GetGroup returns the status of a mission group, based on its name, if one
+// has been added. If the group doesn't exist this just returns nil.
+func GetGroup(name string) *GroupStatus {
+	registryMu.RLock()
+	g, ok := registry.groups[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return g.status()
+}
+
+// RemoveGroup purges a mission group from the registry. Its missions are no
+// longer coordinated, so any that are ready blast off straight away. If the
+// group existed this will return the group, otherwise it will return nil.
+func RemoveGroup(name string) *MissionGroup {
+	registryMu.Lock()
+
+	g, ok := registry.groups[name]
+
+	if !ok {
+		registryMu.Unlock()
+		return nil
+	}
+
+	delete(registry.groups, name)
+
+	for _, id := range g.group.Missions {
+		delete(registry.grouped, id)
+	}
+
+	registryMu.Unlock()
+
+	g.mu.Lock()
+
+	g.removed = true
+	stops := g.stops
+	g.stops = nil
+
+	group := g.group
+	group.Missions = append([]uint32(nil), g.group.Missions...)
+
+	g.mu.Unlock()
+
+	// the missions may blast off, so this is done without holding the locks
+	for _, stop := range stops {
+		stop()
+	}
+
+	return &group
+}
+
+// ListGroups returns a slice of the mission group names. They are in no
+// particular order.
+func ListGroups() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	slice := make([]string, 0, len(registry.groups))
+
+	for name := range registry.groups {
+		slice = append(slice, name)
+	}
+
+	return slice
+}
+
+// ungroupMission removes the mission from its group, if it's in one. The
+// mission must be closed, so that it can't blast off when it stops being
+// coordinated. The registryMu must be held by the caller.
+func ungroupMission(id uint32) {
+	name, ok := registry.grouped[id]
+
+	if !ok {
+		return
+	}
+
+	delete(registry.grouped, id)
+
+	g := registry.groups[name]
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if stop, ok := g.stops[id]; ok {
+		stop()
+	}
+
+	delete(g.stops, id)
+	delete(g.members, id)
+	delete(g.ready, id)
+
+	missions := g.group.Missions[:0]
+
+	for _, mid := range g.group.Missions {
+		if mid != id {
+			missions = append(missions, mid)
+		}
+	}
+
+	g.group.Missions = missions
+
+	// the mission may have been the last one the group was waiting for
+	g.check()
+}
@@ -0,0 +1,212 @@
+package f9missioncontrol_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+func tearDownGroups(c *C) {
+	for _, name := range f9missioncontrol.ListGroups() {
+		c.Check(f9missioncontrol.RemoveGroup(name), NotNil)
+	}
+}
+
+func (*TestSuite) TestAddGroup(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+	defer tearDownGroups(c)
+
+	id := randUint32()
+
+	addChainMission(c, id, nil)
+	addChainMission(c, id+1, nil)
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Missions: []uint32{id}})
+	c.Check(err, ErrorMatches, "the mission group must have a name")
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo"})
+	c.Check(err, ErrorMatches, "the mission group must have missions")
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo", Missions: []uint32{id, id + 2}})
+	c.Check(err, ErrorMatches, "Mission with ID [0-9]+ is not registered")
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo", Missions: []uint32{id, id}})
+	c.Check(err, ErrorMatches, "Mission with ID [0-9]+ is in the group more than once")
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo", Missions: []uint32{id}})
+	c.Assert(err, IsNil)
+
+	//
+	// Test that names and missions can't be shared between groups
+	//
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo", Missions: []uint32{id + 1}})
+	c.Check(err, ErrorMatches, `Mission group with name "apollo" already registered`)
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "gemini", Missions: []uint32{id + 1, id}})
+	c.Check(err, ErrorMatches, `Mission with ID [0-9]+ is already in group "apollo"`)
+
+	c.Check(f9missioncontrol.ListGroups(), DeepEquals, []string{"apollo"})
+
+	status := f9missioncontrol.GetGroup("apollo")
+	c.Assert(status, NotNil)
+	c.Check(status.Missions, DeepEquals, []uint32{id})
+	c.Check(status.Ready, DeepEquals, []uint32{})
+	c.Check(status.LaunchTime.IsZero(), Equals, true)
+
+	c.Check(f9missioncontrol.GetGroup("gemini"), IsNil)
+	c.Check(f9missioncontrol.RemoveGroup("gemini"), IsNil)
+
+	//
+	// Test that missions which can't coordinate their launch are rejected
+	//
+	mc, _ := addChainMission(c, id+2, nil)
+	mc.Mission = baseMission{mc.Mission}
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "gemini", Missions: []uint32{id + 2}})
+	c.Check(err, ErrorMatches, "Mission with ID [0-9]+ does not support coordinated launches")
+}
+
+func (*TestSuite) TestGroup_Launch(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+	defer tearDownGroups(c)
+
+	clock := f9missiontest.NewClock(time.Now())
+	id := randUint32()
+
+	first, firstKey := addChainMission(c, id, clock)
+	second, secondKey := addChainMission(c, id+1, clock)
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{
+		Name:      "apollo",
+		Missions:  []uint32{id, id + 1},
+		Countdown: time.Second * 5,
+		Clock:     clock,
+	})
+	c.Assert(err, IsNil)
+
+	c.Assert(first.Initiate(testOwner), IsNil)
+	c.Assert(second.Initiate(testOwner), IsNil)
+
+	//
+	// Test that a ready mission holds for the rest of the group
+	//
+	_, err = first.Mission.UpdateVote(firstKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Minute)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(first.Mission.(*f9mission.Mission).Holding(), Equals, true)
+
+	status := f9missioncontrol.GetGroup("apollo")
+	c.Check(status.Ready, DeepEquals, []uint32{id})
+	c.Check(status.LaunchTime.IsZero(), Equals, true)
+
+	//
+	// Test that the launch is set once they're all ready, and cancelled if
+	// one stops being ready
+	//
+	_, err = second.Mission.UpdateVote(secondKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	status = f9missioncontrol.GetGroup("apollo")
+	c.Check(status.Ready, DeepEquals, []uint32{id, id + 1})
+	c.Check(status.LaunchTime, Equals, clock.Now().Add(time.Second*5))
+
+	_, err = second.Mission.UpdateVote(secondKey, f9mission.VoteNo)
+	c.Assert(err, IsNil)
+
+	status = f9missioncontrol.GetGroup("apollo")
+	c.Check(status.Ready, DeepEquals, []uint32{id})
+	c.Check(status.LaunchTime.IsZero(), Equals, true)
+
+	clock.Advance(time.Second * 5)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateVoting)
+
+	//
+	// Test that the missions blast off together at the launch time
+	//
+	_, err = second.Mission.UpdateVote(secondKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Second * 4)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateVoting)
+
+	clock.Advance(time.Second)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	status = f9missioncontrol.GetGroup("apollo")
+	c.Check(status.Ready, DeepEquals, []uint32{})
+	c.Check(status.LaunchTime.IsZero(), Equals, true)
+
+	clock.Advance(time.Second)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateFinished)
+	c.Check(second.Mission.CurrentState(), Equals, f9mission.StateFinished)
+
+	//
+	// Test that removing the mission the group is waiting for launches the
+	// rest of it
+	//
+	c.Assert(first.Initiate(testOwner), IsNil)
+
+	_, err = first.Mission.UpdateVote(firstKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	c.Check(f9missioncontrol.RemoveMission(id+1), NotNil)
+
+	status = f9missioncontrol.GetGroup("apollo")
+	c.Check(status.Missions, DeepEquals, []uint32{id})
+	c.Check(status.LaunchTime, Equals, clock.Now().Add(time.Second*5))
+
+	clock.Advance(time.Second * 5)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateBlastoffing)
+}
+
+func (*TestSuite) TestRemoveGroup(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+	defer tearDownGroups(c)
+
+	clock := f9missiontest.NewClock(time.Now())
+	id := randUint32()
+
+	first, firstKey := addChainMission(c, id, clock)
+	addChainMission(c, id+1, clock)
+
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "apollo", Missions: []uint32{id, id + 1}})
+	c.Assert(err, IsNil)
+
+	c.Assert(first.Initiate(testOwner), IsNil)
+
+	_, err = first.Mission.UpdateVote(firstKey, f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateVoting)
+
+	//
+	// Test that the missions are no longer held once the group is removed
+	//
+	group := f9missioncontrol.RemoveGroup("apollo")
+	c.Assert(group, NotNil)
+	c.Check(group.Missions, DeepEquals, []uint32{id, id + 1})
+	c.Check(group.Countdown, Equals, f9missioncontrol.DefaultGroupCountdown)
+
+	c.Check(first.Mission.CurrentState(), Equals, f9mission.StateBlastoffing)
+	c.Check(f9missioncontrol.ListGroups(), DeepEquals, []string{})
+
+	// the missions can be grouped again
+	err = f9missioncontrol.AddGroup(&f9missioncontrol.MissionGroup{Name: "gemini", Missions: []uint32{id, id + 1}})
+	c.Check(err, IsNil)
+}
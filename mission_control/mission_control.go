@@ -1,12 +1,24 @@
 package f9missioncontrol
 
 import (
+	"crypto/ed25519"
+	"errors"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/theckman/falcon9/crew"
 	"github.com/theckman/falcon9/mission"
 )
 
+// ErrNoAuthenticator is the error returned from Join() if the MissionControl
+// has no Authenticator to verify the crew member with.
+var ErrNoAuthenticator = errors.New("mission control has no authenticator configured")
+
+// ErrNotSupported is the error returned when the mission doesn't implement the
+// optional f9mission interface an operation needs.
+var ErrNotSupported = errors.New("the mission does not support this operation")
+
 type client struct {
 	conn net.Conn
 	out  chan []byte
@@ -16,5 +28,108 @@ type client struct {
 // MissionControl is the controller of a mission.
 type MissionControl struct {
 	Mission f9mission.Interface
+
+	// Auth is used to authenticate crew members joining the mission.
+	Auth *Authenticator
+
+	// Hasher is used to make the HashedKey of crew members joining the
+	// mission from their public key. If nil, f9crew.SHA256Hasher is used.
+	Hasher f9crew.Hasher
+
+	// Owner is the HashedKey of the crew member who owns the mission. The
+	// owner can do anything with the mission, including making other crew
+	// members admins. A mission without an owner can't be administered.
+	Owner string
+
+	// Template is the name of the template the mission was created from
+	// using CreateMission(), if any.
+	Template string
+
+	// Clock is used for the expiry of invites minted by MintInvite(). If
+	// nil, f9mission.SystemClock is used.
+	Clock f9mission.Clock
+
 	clients map[string]*client
+
+	join   joinAuthorization
+	admins map[string]struct{}
+	mu     sync.Mutex
+}
+
+// JoinRequest is a request from a client to join the mission as a crew
+// member. The Nonce is a challenge issued by the MissionControl's
+// Authenticator, and the Signature is the ChallengeMessage() of the Nonce,
+// Name, and mission ID signed by the crew member's private key.
+type JoinRequest struct {
+	Name      string
+	PublicKey ed25519.PublicKey
+	Nonce     []byte
+	Signature []byte
+
+	// Password is the mission password, for the JoinPassword policy.
+	Password string
+
+	// InviteCode is a code minted by MintInvite(), for the JoinInvite policy.
+	InviteCode string
+
+	// Replace is passed through to the mission's AddCrew() function.
+	Replace bool
+}
+
+// Join authenticates the crew member making the request, checks that they
+// are authorized to join by the mission's JoinPolicy, and adds them to the
+// mission. The crew member added to the mission is returned.
+func (mc *MissionControl) Join(req *JoinRequest) (f9crew.Interface, error) {
+	if mc.Auth == nil {
+		return nil, ErrNoAuthenticator
+	}
+
+	if req == nil {
+		return nil, errors.New("join request cannot be nil")
+	}
+
+	hasher := mc.Hasher
+
+	if hasher == nil {
+		hasher = f9crew.SHA256Hasher
+	}
+
+	crew, err := mc.Auth.Verify(req.Name, req.PublicKey, mc.Mission.ID(), req.Nonce, req.Signature, hasher)
+
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := mc.authorizeJoin(req, crew.HashedKey())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.Mission.AddCrew(crew, req.Replace); err != nil {
+		done(false)
+		return nil, err
+	}
+
+	done(true)
+
+	return crew, nil
+}
+
+// now returns the current time, using the MissionControl's Clock.
+func (mc *MissionControl) now() time.Time {
+	if mc.Clock == nil {
+		return f9mission.SystemClock.Now()
+	}
+
+	return mc.Clock.Now()
+}
+
+// close closes the mission, if there is one and it implements
+// f9mission.InterfaceLifecycle. The error from closing it is ignored, as the
+// mission is being discarded.
+func (mc *MissionControl) close() {
+	if lc, ok := mc.Mission.(f9mission.InterfaceLifecycle); ok {
+		lc.Close()
+	}
 }
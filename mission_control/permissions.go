@@ -0,0 +1,164 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+)
+
+// PermissionError is the error returned when a crew member tries to do
+// something with the mission that they aren't permitted to do. The error
+// message is meant to be shown to the crew member.
+type PermissionError struct {
+	// HashedKey identifies the crew member who was denied.
+	HashedKey string
+
+	// Action is what the crew member tried to do.
+	Action string
+
+	// Required is the role required for the action: "owner" or "admin".
+	Required string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("permission denied: only the mission %s may %s", e.Required, e.Action)
+}
+
+// ErrCannotKickOwner is the error returned from Kick() if the crew member
+// being removed is the mission's owner.
+var ErrCannotKickOwner = errors.New("the mission owner cannot be removed from the mission")
+
+// IsOwner returns whether the crew member is the mission's owner.
+func (mc *MissionControl) IsOwner(hashedKey string) bool {
+	return mc.Owner != "" && hashedKey == mc.Owner
+}
+
+// IsAdmin returns whether the crew member is an admin of the mission. The
+// mission's owner is always an admin.
+func (mc *MissionControl) IsAdmin(hashedKey string) bool {
+	if mc.IsOwner(hashedKey) {
+		return true
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	_, ok := mc.admins[hashedKey]
+
+	return ok
+}
+
+// requireOwner returns a *PermissionError if the crew member isn't the owner.
+func (mc *MissionControl) requireOwner(by, action string) error {
+	if !mc.IsOwner(by) {
+		return &PermissionError{HashedKey: by, Action: action, Required: "owner"}
+	}
+
+	return nil
+}
+
+// requireAdmin returns a *PermissionError if the crew member isn't an admin.
+func (mc *MissionControl) requireAdmin(by, action string) error {
+	if !mc.IsAdmin(by) {
+		return &PermissionError{HashedKey: by, Action: action, Required: "admin"}
+	}
+
+	return nil
+}
+
+// AddAdmin makes a crew member an admin of the mission. Only the mission's
+// owner may add admins.
+func (mc *MissionControl) AddAdmin(by, hashedKey string) error {
+	if err := mc.requireOwner(by, "add admins"); err != nil {
+		return err
+	}
+
+	if hashedKey == "" {
+		return errors.New("hashedKey parameter cannot be an empty string")
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.admins == nil {
+		mc.admins = make(map[string]struct{})
+	}
+
+	mc.admins[hashedKey] = struct{}{}
+
+	return nil
+}
+
+// RemoveAdmin revokes a crew member's admin permissions. Only the mission's
+// owner may remove admins.
+func (mc *MissionControl) RemoveAdmin(by, hashedKey string) error {
+	if err := mc.requireOwner(by, "remove admins"); err != nil {
+		return err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	delete(mc.admins, hashedKey)
+
+	return nil
+}
+
+// Initiate starts the mission's Go/No-Go vote on behalf of a crew member.
+// Only admins may initiate the mission, and if it's in the registry with
+// upstream missions they must all have finished.
+func (mc *MissionControl) Initiate(by string) error {
+	if err := mc.requireAdmin(by, "initiate the Go/No-Go"); err != nil {
+		return err
+	}
+
+	return initiateMission(mc.Mission.ID(), mc)
+}
+
+// ResolveConditional resolves a crew member's conditional vote into a Yes or
+// No on behalf of another crew member. Only admins may resolve the votes of
+// other crew, but crew members may always resolve their own. If the mission
+// doesn't implement f9mission.InterfaceConditionalVotes, this will return a
+// ErrNotSupported error.
+func (mc *MissionControl) ResolveConditional(by, hashedKey string, vote f9mission.Vote) (bool, error) {
+	if by != hashedKey {
+		if err := mc.requireAdmin(by, "resolve the conditional votes of other crew"); err != nil {
+			return false, err
+		}
+	}
+
+	cv, ok := mc.Mission.(f9mission.InterfaceConditionalVotes)
+
+	if !ok {
+		return false, ErrNotSupported
+	}
+
+	return cv.ResolveConditional(hashedKey, vote)
+}
+
+// Kick removes a crew member from the mission on behalf of another crew
+// member. Only admins may kick crew, only the owner may kick other admins,
+// and the owner can't be kicked. Crew members may always remove themselves.
+func (mc *MissionControl) Kick(by, hashedKey string) (f9crew.Interface, error) {
+	if by != hashedKey {
+		if mc.IsOwner(hashedKey) {
+			return nil, ErrCannotKickOwner
+		}
+
+		var err error
+
+		if mc.IsAdmin(hashedKey) {
+			err = mc.requireOwner(by, "remove admins from the mission")
+		} else {
+			err = mc.requireAdmin(by, "remove crew from the mission")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mc.Mission.RemoveCrew(hashedKey)
+}
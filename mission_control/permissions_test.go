@@ -0,0 +1,207 @@
+package f9missioncontrol_test
+
+import (
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+// addTestCrew adds crew members to the mission, returning their HashedKeys.
+func addTestCrew(c *C, mc *f9missioncontrol.MissionControl, names ...string) []string {
+	keys := make([]string, len(names))
+
+	for i, name := range names {
+		crew, err := f9crew.NewCrewMember(name, name)
+		c.Assert(err, IsNil)
+		c.Assert(mc.Mission.AddCrew(crew, false), IsNil)
+
+		keys[i] = crew.HashedKey()
+	}
+
+	return keys
+}
+
+func (*TestSuite) TestPermissionError(c *C) {
+	err := &f9missioncontrol.PermissionError{HashedKey: "abc", Action: "initiate the Go/No-Go", Required: "admin"}
+	c.Check(err.Error(), Equals, "permission denied: only the mission admin may initiate the Go/No-Go")
+}
+
+func (*TestSuite) TestMissionControl_AddAdmin(c *C) {
+	var err error
+
+	mc := newTestMissionControl(c)
+
+	c.Check(mc.IsOwner(testOwner), Equals, true)
+	c.Check(mc.IsAdmin(testOwner), Equals, true)
+	c.Check(mc.IsAdmin("admin"), Equals, false)
+
+	//
+	// Test that only the owner can add admins
+	//
+	err = mc.AddAdmin("admin", "admin")
+	c.Assert(err, FitsTypeOf, &f9missioncontrol.PermissionError{})
+	c.Check(err.(*f9missioncontrol.PermissionError).HashedKey, Equals, "admin")
+	c.Check(err, ErrorMatches, "permission denied: only the mission owner may add admins")
+
+	c.Check(mc.AddAdmin(testOwner, ""), ErrorMatches, "hashedKey parameter cannot be an empty string")
+
+	c.Assert(mc.AddAdmin(testOwner, "admin"), IsNil)
+	c.Check(mc.IsAdmin("admin"), Equals, true)
+	c.Check(mc.IsOwner("admin"), Equals, false)
+
+	//
+	// Test that admins can't add other admins, or remove them
+	//
+	c.Check(mc.AddAdmin("admin", "admin2"), ErrorMatches, "permission denied: only the mission owner may add admins")
+	c.Check(mc.RemoveAdmin("admin", "admin"), ErrorMatches, "permission denied: only the mission owner may remove admins")
+
+	c.Assert(mc.RemoveAdmin(testOwner, "admin"), IsNil)
+	c.Check(mc.IsAdmin("admin"), Equals, false)
+
+	//
+	// Test that missions without an owner can't be administered
+	//
+	mc.Owner = ""
+
+	c.Check(mc.IsOwner(""), Equals, false)
+	c.Check(mc.IsAdmin(""), Equals, false)
+	c.Check(mc.AddAdmin("", "admin"), NotNil)
+	c.Check(mc.Initiate(""), NotNil)
+}
+
+func (*TestSuite) TestMissionControl_Initiate(c *C) {
+	mc := newTestMissionControl(c)
+	keys := addTestCrew(c, mc, "Jebediah Kerman")
+
+	c.Check(mc.Initiate(keys[0]), ErrorMatches, "permission denied: only the mission admin may initiate the Go/No-Go")
+	c.Check(mc.Mission.CurrentState(), Equals, f9mission.StateReady)
+
+	c.Assert(mc.AddAdmin(testOwner, keys[0]), IsNil)
+
+	c.Assert(mc.Initiate(keys[0]), IsNil)
+	c.Check(mc.Mission.CurrentState(), Equals, f9mission.StateVoting)
+
+	c.Check(mc.Initiate(testOwner), Equals, f9mission.ErrMissionInProgress)
+}
+
+func (*TestSuite) TestMissionControl_ResolveConditional(c *C) {
+	var err error
+
+	mc := newTestMissionControl(c)
+	keys := addTestCrew(c, mc, "Jebediah Kerman", "Bill Kerman")
+	jeb, bill := keys[0], keys[1]
+
+	c.Assert(mc.Initiate(testOwner), IsNil)
+
+	_, err = mc.Mission.(*f9mission.Mission).UpdateVoteConditional(jeb, "once the weather clears")
+	c.Assert(err, IsNil)
+
+	_, err = mc.Mission.(*f9mission.Mission).UpdateVoteConditional(bill, "after the fuel check")
+	c.Assert(err, IsNil)
+
+	//
+	// Test that crew can't resolve the votes of others
+	//
+	_, err = mc.ResolveConditional(bill, jeb, f9mission.VoteYes)
+	c.Check(err, ErrorMatches, "permission denied: only the mission admin may resolve the conditional votes of other crew")
+
+	//
+	// Test that crew can resolve their own, and admins can resolve anyone's
+	//
+	_, err = mc.ResolveConditional(bill, bill, f9mission.VoteNo)
+	c.Check(err, IsNil)
+
+	_, err = mc.ResolveConditional(testOwner, jeb, f9mission.VoteYes)
+	c.Check(err, IsNil)
+
+	tally, _ := mc.Mission.Tally()
+	c.Check(tally, DeepEquals, f9mission.Tally{f9mission.VoteYes: 1, f9mission.VoteNo: 1})
+
+	//
+	// Test that missions without conditional votes are rejected
+	//
+	mc.Mission = baseMission{mc.Mission}
+
+	_, err = mc.ResolveConditional(testOwner, jeb, f9mission.VoteYes)
+	c.Check(err, Equals, f9missioncontrol.ErrNotSupported)
+}
+
+// baseMission only implements f9mission.Interface, and none of the optional
+// interfaces, like a consumer's own mission implementation.
+type baseMission struct {
+	f9mission.Interface
+}
+
+func (*TestSuite) TestMissionControl_Kick(c *C) {
+	var crew f9crew.Interface
+	var err error
+
+	mc := newTestMissionControl(c)
+	keys := addTestCrew(c, mc, "Jebediah Kerman", "Bill Kerman", "Bob Kerman")
+	jeb, bill, bob := keys[0], keys[1], keys[2]
+
+	c.Assert(mc.AddAdmin(testOwner, jeb), IsNil)
+
+	//
+	// Test that crew can't kick others
+	//
+	crew, err = mc.Kick(bill, bob)
+	c.Check(err, ErrorMatches, "permission denied: only the mission admin may remove crew from the mission")
+	c.Check(crew, IsNil)
+
+	//
+	// Test that admins can't kick other admins, or the owner
+	//
+	c.Assert(mc.AddAdmin(testOwner, bill), IsNil)
+
+	crew, err = mc.Kick(jeb, bill)
+	c.Check(err, ErrorMatches, "permission denied: only the mission owner may remove admins from the mission")
+	c.Check(crew, IsNil)
+
+	crew, err = mc.Kick(jeb, testOwner)
+	c.Check(err, Equals, f9missioncontrol.ErrCannotKickOwner)
+	c.Check(crew, IsNil)
+
+	//
+	// Test that admins can kick crew
+	//
+	crew, err = mc.Kick(jeb, bob)
+	c.Assert(err, IsNil)
+	c.Check(crew.Name(), Equals, "Bob Kerman")
+
+	//
+	// Test that the owner can kick admins, and crew can leave
+	//
+	crew, err = mc.Kick(testOwner, bill)
+	c.Assert(err, IsNil)
+	c.Check(crew.Name(), Equals, "Bill Kerman")
+
+	crew, err = mc.Kick(jeb, jeb)
+	c.Assert(err, IsNil)
+	c.Check(crew.Name(), Equals, "Jebediah Kerman")
+
+	c.Check(len(mc.Mission.Crew()), Equals, 0)
+
+	crew, err = mc.Kick(testOwner, bob)
+	c.Check(err, Equals, f9mission.ErrCrewMemberNotPresent)
+	c.Check(crew, IsNil)
+}
+
+func (*TestSuite) TestMissionControl_PolicyPermissions(c *C) {
+	var err error
+
+	mc := newTestMissionControl(c)
+
+	c.Check(mc.SetJoinPolicy("crew", f9missioncontrol.JoinInvite), FitsTypeOf, &f9missioncontrol.PermissionError{})
+	c.Check(mc.JoinPolicy(), Equals, f9missioncontrol.JoinOpen)
+
+	c.Check(mc.SetPassword("crew", "hunter2"), FitsTypeOf, &f9missioncontrol.PermissionError{})
+	c.Check(mc.Allow("crew", "crew"), FitsTypeOf, &f9missioncontrol.PermissionError{})
+	c.Check(mc.Disallow("crew", "crew"), FitsTypeOf, &f9missioncontrol.PermissionError{})
+	c.Check(mc.RevokeInvite("crew", "code"), FitsTypeOf, &f9missioncontrol.PermissionError{})
+
+	_, err = mc.MintInvite("crew", 0)
+	c.Check(err, FitsTypeOf, &f9missioncontrol.PermissionError{})
+}
@@ -0,0 +1,279 @@
+package f9missioncontrol
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// JoinPolicy is the type that defines who is allowed to join a mission. The
+// default value is to allow anyone to join.
+type JoinPolicy uint8
+
+const (
+	// JoinOpen is the JoinPolicy allowing anyone who knows the mission ID to
+	// join the mission.
+	JoinOpen JoinPolicy = iota
+
+	// JoinPassword is the JoinPolicy requiring the password set with
+	// SetPassword() to join the mission.
+	JoinPassword
+
+	// JoinInvite is the JoinPolicy requiring a single-use invite code, minted
+	// with MintInvite(), to join the mission.
+	JoinInvite
+
+	// JoinAllowList is the JoinPolicy only allowing crew whose HashedKey was
+	// added with Allow() to join the mission.
+	JoinAllowList
+)
+
+func (p JoinPolicy) String() string {
+	switch p {
+	case JoinOpen:
+		return "Open"
+	case JoinPassword:
+		return "Password"
+	case JoinInvite:
+		return "Invite"
+	case JoinAllowList:
+		return "AllowList"
+	default:
+		return "Unknown"
+	}
+}
+
+// ErrIncorrectPassword is the error returned from Join() if the mission
+// requires a password and the one provided doesn't match.
+var ErrIncorrectPassword = errors.New("the mission password is incorrect")
+
+// ErrInvalidInvite is the error returned from Join() if the mission requires
+// an invite and the code provided is unknown, expired, revoked, or used.
+var ErrInvalidInvite = errors.New("the invite code is not valid for this mission")
+
+// ErrNotAllowed is the error returned from Join() if the mission uses an
+// allow-list and the crew member is not on it.
+var ErrNotAllowed = errors.New("the crew member is not allowed to join this mission")
+
+// ErrInviteNotFound is the error returned from RevokeInvite() if the invite
+// code doesn't exist.
+var ErrInviteNotFound = errors.New("the invite code does not exist")
+
+type joinAuthorization struct {
+	policy    JoinPolicy
+	password  []byte
+	invites   map[string]time.Time
+	allowList map[string]struct{}
+
+	// pending is the invites consumed by joins that are still in progress,
+	// which are restored if the crew member can't be added, unless they're
+	// revoked in the meantime
+	pending map[string]time.Time
+}
+
+// JoinPolicy returns the policy used to authorize crew joining the mission.
+func (mc *MissionControl) JoinPolicy() JoinPolicy {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	return mc.join.policy
+}
+
+// SetJoinPolicy sets the policy used to authorize crew joining the mission.
+// Crew already assigned to the mission are not affected. Only admins may
+// change the join policy.
+func (mc *MissionControl) SetJoinPolicy(by string, policy JoinPolicy) error {
+	if err := mc.requireAdmin(by, "change the join policy"); err != nil {
+		return err
+	}
+
+	if policy > JoinAllowList {
+		return errors.New("unknown join policy")
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.join.policy = policy
+
+	return nil
+}
+
+// SetPassword sets the password required by the JoinPassword policy. Only
+// admins may set the password.
+func (mc *MissionControl) SetPassword(by, password string) error {
+	if err := mc.requireAdmin(by, "set the mission password"); err != nil {
+		return err
+	}
+
+	if password == "" {
+		return errors.New("the mission password cannot be an empty value")
+	}
+
+	sum := sha256.Sum256([]byte(password))
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.join.password = sum[:]
+
+	return nil
+}
+
+// MintInvite creates a single-use invite code for the JoinInvite policy. The
+// code expires after ttl. Only admins may mint invites.
+func (mc *MissionControl) MintInvite(by string, ttl time.Duration) (string, error) {
+	if err := mc.requireAdmin(by, "mint invites"); err != nil {
+		return "", err
+	}
+
+	if ttl <= 0 {
+		return "", errors.New("the invite TTL must be greater than zero")
+	}
+
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := hex.EncodeToString(b)
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.join.invites == nil {
+		mc.join.invites = make(map[string]time.Time)
+	}
+
+	now := mc.now()
+
+	// purge any expired invites that were never used
+	for k, expires := range mc.join.invites {
+		if now.After(expires) {
+			delete(mc.join.invites, k)
+		}
+	}
+
+	mc.join.invites[code] = now.Add(ttl)
+
+	return code, nil
+}
+
+// RevokeInvite revokes an invite code that hasn't been used yet. Only admins
+// may revoke invites.
+func (mc *MissionControl) RevokeInvite(by, code string) error {
+	if err := mc.requireAdmin(by, "revoke invites"); err != nil {
+		return err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if _, ok := mc.join.invites[code]; ok {
+		delete(mc.join.invites, code)
+		return nil
+	}
+
+	// the invite is being used by a join that's in progress, so it mustn't
+	// be restored if that join fails
+	if _, ok := mc.join.pending[code]; ok {
+		delete(mc.join.pending, code)
+		return nil
+	}
+
+	return ErrInviteNotFound
+}
+
+// Allow adds a crew member's HashedKey to the allow-list used by the
+// JoinAllowList policy. Only admins may change the allow-list.
+func (mc *MissionControl) Allow(by, hashedKey string) error {
+	if err := mc.requireAdmin(by, "change the allow-list"); err != nil {
+		return err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.join.allowList == nil {
+		mc.join.allowList = make(map[string]struct{})
+	}
+
+	mc.join.allowList[hashedKey] = struct{}{}
+
+	return nil
+}
+
+// Disallow removes a crew member's HashedKey from the allow-list used by the
+// JoinAllowList policy. Crew already assigned to the mission are not removed.
+// Only admins may change the allow-list.
+func (mc *MissionControl) Disallow(by, hashedKey string) error {
+	if err := mc.requireAdmin(by, "change the allow-list"); err != nil {
+		return err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	delete(mc.join.allowList, hashedKey)
+
+	return nil
+}
+
+// authorizeJoin checks the join request against the mission's join policy.
+// The function returned must be called once the crew member has been added to
+// the mission, or couldn't be, so that a consumed invite code can be used
+// again if they weren't added and it hasn't been revoked.
+func (mc *MissionControl) authorizeJoin(req *JoinRequest, hashedKey string) (func(joined bool), error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	done := func(bool) {}
+
+	switch mc.join.policy {
+	case JoinPassword:
+		sum := sha256.Sum256([]byte(req.Password))
+
+		if len(mc.join.password) == 0 || subtle.ConstantTimeCompare(sum[:], mc.join.password) != 1 {
+			return nil, ErrIncorrectPassword
+		}
+	case JoinInvite:
+		expires, ok := mc.join.invites[req.InviteCode]
+
+		if !ok || mc.now().After(expires) {
+			return nil, ErrInvalidInvite
+		}
+
+		delete(mc.join.invites, req.InviteCode)
+
+		if mc.join.pending == nil {
+			mc.join.pending = make(map[string]time.Time)
+		}
+
+		mc.join.pending[req.InviteCode] = expires
+
+		done = func(joined bool) {
+			mc.mu.Lock()
+			defer mc.mu.Unlock()
+
+			if _, ok := mc.join.pending[req.InviteCode]; !ok {
+				return
+			}
+
+			delete(mc.join.pending, req.InviteCode)
+
+			if !joined {
+				mc.join.invites[req.InviteCode] = expires
+			}
+		}
+	case JoinAllowList:
+		if _, ok := mc.join.allowList[hashedKey]; !ok {
+			return nil, ErrNotAllowed
+		}
+	}
+
+	return done, nil
+}
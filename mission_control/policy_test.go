@@ -0,0 +1,252 @@
+package f9missioncontrol_test
+
+import (
+	"crypto/ed25519"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+// newJoinRequest returns a signed join request for the crew member, using a
+// challenge from the MissionControl's authenticator.
+func newJoinRequest(c *C, mc *f9missioncontrol.MissionControl, name string, pub ed25519.PublicKey, priv ed25519.PrivateKey) *f9missioncontrol.JoinRequest {
+	nonce, err := mc.Auth.Challenge()
+	c.Assert(err, IsNil)
+
+	return &f9missioncontrol.JoinRequest{
+		Name:      name,
+		PublicKey: pub,
+		Nonce:     nonce,
+		Signature: ed25519.Sign(priv, f9missioncontrol.ChallengeMessage(nonce, name, mc.Mission.ID())),
+	}
+}
+
+// testOwner is the HashedKey of the owner of missions from newTestMissionControl.
+const testOwner = "owner"
+
+func newTestMissionControl(c *C) *f9missioncontrol.MissionControl {
+	mission, err := f9mission.NewMission(&f9mission.MissionParams{})
+	c.Assert(err, IsNil)
+
+	return &f9missioncontrol.MissionControl{
+		Mission: mission,
+		Auth:    f9missioncontrol.NewAuthenticator(0),
+		Owner:   testOwner,
+	}
+}
+
+func (*TestSuite) TestJoinPolicy_String(c *C) {
+	c.Check(f9missioncontrol.JoinOpen.String(), Equals, "Open")
+	c.Check(f9missioncontrol.JoinPassword.String(), Equals, "Password")
+	c.Check(f9missioncontrol.JoinInvite.String(), Equals, "Invite")
+	c.Check(f9missioncontrol.JoinAllowList.String(), Equals, "AllowList")
+	c.Check(f9missioncontrol.JoinPolicy(100).String(), Equals, "Unknown")
+}
+
+func (*TestSuite) TestMissionControl_SetJoinPolicy(c *C) {
+	mc := newTestMissionControl(c)
+
+	c.Check(mc.JoinPolicy(), Equals, f9missioncontrol.JoinOpen)
+
+	c.Check(mc.SetJoinPolicy(testOwner, f9missioncontrol.JoinPolicy(100)), ErrorMatches, "unknown join policy")
+	c.Check(mc.JoinPolicy(), Equals, f9missioncontrol.JoinOpen)
+
+	c.Assert(mc.SetJoinPolicy(testOwner, f9missioncontrol.JoinInvite), IsNil)
+	c.Check(mc.JoinPolicy(), Equals, f9missioncontrol.JoinInvite)
+}
+
+func (*TestSuite) TestMissionControl_JoinPassword(c *C) {
+	var req *f9missioncontrol.JoinRequest
+	var err error
+
+	mc := newTestMissionControl(c)
+	pub, priv := genKey(c)
+
+	c.Assert(mc.SetJoinPolicy(testOwner, f9missioncontrol.JoinPassword), IsNil)
+
+	//
+	// Test that no password is accepted before one is set
+	//
+	req = newJoinRequest(c, mc, "Jebediah Kerman", pub, priv)
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrIncorrectPassword)
+
+	c.Check(mc.SetPassword(testOwner, ""), ErrorMatches, "the mission password cannot be an empty value")
+	c.Assert(mc.SetPassword(testOwner, "hunter2"), IsNil)
+
+	req = newJoinRequest(c, mc, "Jebediah Kerman", pub, priv)
+	req.Password = "hunter3"
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrIncorrectPassword)
+	c.Check(len(mc.Mission.Crew()), Equals, 0)
+
+	req = newJoinRequest(c, mc, "Jebediah Kerman", pub, priv)
+	req.Password = "hunter2"
+
+	_, err = mc.Join(req)
+	c.Check(err, IsNil)
+	c.Check(len(mc.Mission.Crew()), Equals, 1)
+}
+
+func (*TestSuite) TestMissionControl_JoinInvite(c *C) {
+	var req *f9missioncontrol.JoinRequest
+	var code string
+	var err error
+
+	clock := f9missiontest.NewClock(scheduleEpoch)
+
+	mc := newTestMissionControl(c)
+	mc.Clock = clock
+
+	jebPub, jebPriv := genKey(c)
+	billPub, billPriv := genKey(c)
+
+	c.Assert(mc.SetJoinPolicy(testOwner, f9missioncontrol.JoinInvite), IsNil)
+
+	_, err = mc.MintInvite(testOwner, 0)
+	c.Check(err, ErrorMatches, "the invite TTL must be greater than zero")
+
+	code, err = mc.MintInvite(testOwner, time.Minute)
+	c.Assert(err, IsNil)
+	c.Check(len(code), Equals, 32)
+
+	//
+	// Test that an invite code is required
+	//
+	req = newJoinRequest(c, mc, "Jebediah Kerman", jebPub, jebPriv)
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidInvite)
+
+	//
+	// Test that a failed join doesn't use up the invite
+	//
+	jeb, err := f9crew.NewCrewMemberFromPublicKey("Jebediah Kerman", jebPub, f9crew.SHA256Hasher)
+	c.Assert(err, IsNil)
+	c.Assert(mc.Mission.AddCrew(jeb, false), IsNil)
+
+	req = newJoinRequest(c, mc, "Jebediah Kerman", jebPub, jebPriv)
+	req.InviteCode = code
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9mission.ErrCrewMemberAlreadyPresent)
+
+	//
+	// Test that the invite can only be used once
+	//
+	req = newJoinRequest(c, mc, "Bill Kerman", billPub, billPriv)
+	req.InviteCode = code
+
+	_, err = mc.Join(req)
+	c.Check(err, IsNil)
+
+	req = newJoinRequest(c, mc, "Bill Kerman", billPub, billPriv)
+	req.InviteCode = code
+	req.Replace = true
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidInvite)
+
+	//
+	// Test that revoked invites can't be used
+	//
+	code, err = mc.MintInvite(testOwner, time.Minute)
+	c.Assert(err, IsNil)
+
+	c.Assert(mc.RevokeInvite(testOwner, code), IsNil)
+	c.Check(mc.RevokeInvite(testOwner, code), Equals, f9missioncontrol.ErrInviteNotFound)
+
+	req = newJoinRequest(c, mc, "Bill Kerman", billPub, billPriv)
+	req.InviteCode = code
+	req.Replace = true
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidInvite)
+
+	//
+	// Test that an invite revoked while a join is using it isn't restored
+	// when the join fails
+	//
+	code, err = mc.MintInvite(testOwner, time.Minute)
+	c.Assert(err, IsNil)
+
+	mission := mc.Mission
+	mc.Mission = revokingMission{mission, func() { c.Check(mc.RevokeInvite(testOwner, code), IsNil) }}
+
+	req = newJoinRequest(c, mc, "Jebediah Kerman", jebPub, jebPriv)
+	req.InviteCode = code
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9mission.ErrCrewMemberAlreadyPresent)
+
+	mc.Mission = mission
+
+	c.Check(mc.RevokeInvite(testOwner, code), Equals, f9missioncontrol.ErrInviteNotFound)
+
+	req = newJoinRequest(c, mc, "Bill Kerman", billPub, billPriv)
+	req.InviteCode = code
+	req.Replace = true
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidInvite)
+
+	//
+	// Test that expired invites can't be used
+	//
+	code, err = mc.MintInvite(testOwner, time.Millisecond*10)
+	c.Assert(err, IsNil)
+
+	clock.Advance(time.Millisecond * 20)
+
+	req = newJoinRequest(c, mc, "Bill Kerman", billPub, billPriv)
+	req.InviteCode = code
+	req.Replace = true
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrInvalidInvite)
+}
+
+// revokingMission calls revoke before adding crew to the mission, like an
+// admin revoking an invite while a join is using it.
+type revokingMission struct {
+	f9mission.Interface
+	revoke func()
+}
+
+func (m revokingMission) AddCrew(crew f9crew.Interface, replace bool) error {
+	m.revoke()
+	return m.Interface.AddCrew(crew, replace)
+}
+
+func (*TestSuite) TestMissionControl_JoinAllowList(c *C) {
+	var err error
+
+	mc := newTestMissionControl(c)
+	pub, priv := genKey(c)
+
+	c.Assert(mc.SetJoinPolicy(testOwner, f9missioncontrol.JoinAllowList), IsNil)
+
+	_, err = mc.Join(newJoinRequest(c, mc, "Jebediah Kerman", pub, priv))
+	c.Check(err, Equals, f9missioncontrol.ErrNotAllowed)
+
+	c.Assert(mc.Allow(testOwner, f9crew.HashKey(string(pub))), IsNil)
+
+	_, err = mc.Join(newJoinRequest(c, mc, "Jebediah Kerman", pub, priv))
+	c.Check(err, IsNil)
+
+	c.Assert(mc.Disallow(testOwner, f9crew.HashKey(string(pub))), IsNil)
+
+	req := newJoinRequest(c, mc, "Jebediah Kerman", pub, priv)
+	req.Replace = true
+
+	_, err = mc.Join(req)
+	c.Check(err, Equals, f9missioncontrol.ErrNotAllowed)
+	c.Check(len(mc.Mission.Crew()), Equals, 1)
+}
@@ -3,10 +3,20 @@ package f9missioncontrol
 import (
 	"fmt"
 	"sync"
+
+	"github.com/theckman/falcon9/crew"
 )
 
 type missionRegistry struct {
-	missions map[uint32]*MissionControl
+	missions  map[uint32]*MissionControl
+	rosters   map[string]*f9crew.Roster
+	templates map[string]*MissionTemplate
+	deps      missionDependencies
+	groups    map[string]*missionGroup
+
+	// grouped is the name of the group each mission is in, keyed by
+	// mission ID.
+	grouped map[uint32]string
 }
 
 var (
@@ -40,24 +50,54 @@ func AddMission(id uint32, mission *MissionControl) error {
 	}
 
 	registry.missions[id] = mission
+	watchMission(id, mission)
 
 	return nil
 }
 
-// RemoveMission purges a mission from the mission registry. If the mission existed
-// this will return the mission, otherwise it will return nil.
+// RemoveMission purges a mission from the mission registry, along with its
+// dependencies and group membership, and closes it to stop its timers. If the
+// mission existed this will return the mission, otherwise it will return nil.
 func RemoveMission(id uint32) *MissionControl {
 	registryMu.Lock()
 	defer registryMu.Unlock()
 
 	if mission, ok := registry.missions[id]; ok {
 		delete(registry.missions, id)
+		unwatchMission(id)
+		mission.close()
+		ungroupMission(id)
 		return mission
 	}
 
 	return nil
 }
 
+// DeleteMission removes a mission from the mission registry on behalf of a
+// crew member, and closes it. Only the mission's owner may delete it. If the
+// mission doesn't exist this will return a nil *MissionControl and a nil error.
+func DeleteMission(id uint32, by string) (*MissionControl, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	mission, ok := registry.missions[id]
+
+	if !ok {
+		return nil, nil
+	}
+
+	if err := mission.requireOwner(by, "delete the mission"); err != nil {
+		return nil, err
+	}
+
+	delete(registry.missions, id)
+	unwatchMission(id)
+	mission.close()
+	ungroupMission(id)
+
+	return mission, nil
+}
+
 // ListMissions returns a slice of the mission IDs. They are in no particular order.
 func ListMissions() []uint32 {
 	registryMu.RLock()
@@ -77,4 +117,11 @@ func ListMissions() []uint32 {
 
 func init() {
 	registry.missions = make(map[uint32]*MissionControl)
+	registry.rosters = make(map[string]*f9crew.Roster)
+	registry.templates = make(map[string]*MissionTemplate)
+	registry.deps.upstream = make(map[uint32]map[uint32]struct{})
+	registry.deps.auto = make(map[uint32]bool)
+	registry.deps.watches = make(map[uint32]func())
+	registry.groups = make(map[string]*missionGroup)
+	registry.grouped = make(map[uint32]string)
 }
@@ -164,4 +164,53 @@ func (*TestSuite) TestRemoveMission(c *C) {
 	c.Check(mIfc.Mission.Name(), Equals, "testName")
 	c.Check(mIfc.Mission.ID(), Equals, id)
 	c.Check(len(f9missioncontrol.ListMissions()), Equals, 0)
+
+	// the mission is closed once it's removed
+	c.Check(mission.Initiate(), Equals, f9mission.ErrMissionClosed)
+}
+
+func (*TestSuite) TestDeleteMission(c *C) {
+	var mIfc *f9missioncontrol.MissionControl
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	id := randUint32()
+
+	mission, err := f9mission.NewMission(&f9mission.MissionParams{ID: id})
+	c.Assert(err, IsNil)
+
+	mc := &f9missioncontrol.MissionControl{Mission: mission, Owner: "owner"}
+
+	err = f9missioncontrol.AddMission(id, mc)
+	c.Assert(err, IsNil)
+
+	//
+	// Test when mission does not exist
+	//
+	mIfc, err = f9missioncontrol.DeleteMission(id+1, "owner")
+	c.Check(err, IsNil)
+	c.Check(mIfc, IsNil)
+
+	//
+	// Test that only the owner can delete the mission
+	//
+	mIfc, err = f9missioncontrol.DeleteMission(id, "crew")
+	c.Check(err, ErrorMatches, "permission denied: only the mission owner may delete the mission")
+	c.Check(mIfc, IsNil)
+	c.Check(f9missioncontrol.GetMission(id), NotNil)
+
+	select {
+	case <-mission.Done():
+		c.Fatal("the mission was closed without being deleted")
+	default:
+	}
+
+	mIfc, err = f9missioncontrol.DeleteMission(id, "owner")
+	c.Assert(err, IsNil)
+	c.Check(mIfc, Equals, mc)
+	c.Check(f9missioncontrol.GetMission(id), IsNil)
+
+	<-mission.Done()
 }
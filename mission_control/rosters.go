@@ -0,0 +1,66 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/theckman/falcon9/crew"
+)
+
+// AddRoster adds a roster to the registry, so that it can be attached to
+// missions by name. This will only return an error when the roster has no
+// name, or the registry already has a roster with that name.
+func AddRoster(roster *f9crew.Roster) error {
+	if roster == nil || roster.Name == "" {
+		return errors.New("the roster must have a name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry.rosters[roster.Name]; ok {
+		return fmt.Errorf("Roster with name %q already registered", roster.Name)
+	}
+
+	registry.rosters[roster.Name] = roster.Copy()
+
+	return nil
+}
+
+// GetRoster returns a copy of a roster, based on its name, if one has been
+// added. If the roster doesn't exist this just returns nil.
+func GetRoster(name string) *f9crew.Roster {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return registry.rosters[name].Copy()
+}
+
+// RemoveRoster purges a roster from the registry. Missions the roster is
+// attached to are not affected. If the roster existed this will return the
+// roster, otherwise it will return nil.
+func RemoveRoster(name string) *f9crew.Roster {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if roster, ok := registry.rosters[name]; ok {
+		delete(registry.rosters, name)
+		return roster
+	}
+
+	return nil
+}
+
+// ListRosters returns a slice of the roster names. They are in no particular order.
+func ListRosters() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	slice := make([]string, 0, len(registry.rosters))
+
+	for name := range registry.rosters {
+		slice = append(slice, name)
+	}
+
+	return slice
+}
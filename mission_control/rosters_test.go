@@ -0,0 +1,69 @@
+package f9missioncontrol_test
+
+import (
+	"sort"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+func tearDownRosters(c *C) {
+	for _, name := range f9missioncontrol.ListRosters() {
+		c.Check(f9missioncontrol.RemoveRoster(name), NotNil)
+	}
+}
+
+func (*TestSuite) TestAddRoster(c *C) {
+	// clean up the registry
+	defer tearDownRosters(c)
+
+	c.Check(f9missioncontrol.AddRoster(nil), ErrorMatches, "the roster must have a name")
+	c.Check(f9missioncontrol.AddRoster(&f9crew.Roster{}), ErrorMatches, "the roster must have a name")
+
+	roster := &f9crew.Roster{Name: "Pilots"}
+
+	c.Assert(f9missioncontrol.AddRoster(roster), IsNil)
+	c.Check(f9missioncontrol.GetRoster("Pilots"), DeepEquals, roster)
+	c.Check(f9missioncontrol.GetRoster("Engineers"), IsNil)
+
+	//
+	// Test that the registry's roster can't be modified by callers
+	//
+	roster.Crew = f9crew.Manifest{nil}
+	f9missioncontrol.GetRoster("Pilots").Name = "Engineers"
+
+	c.Check(f9missioncontrol.GetRoster("Pilots"), DeepEquals, &f9crew.Roster{Name: "Pilots"})
+
+	//
+	// Test that you can't register it twice
+	//
+	c.Check(f9missioncontrol.AddRoster(roster), ErrorMatches, `Roster with name "Pilots" already registered`)
+}
+
+func (*TestSuite) TestListRosters(c *C) {
+	// clean up the registry
+	defer tearDownRosters(c)
+
+	c.Assert(f9missioncontrol.AddRoster(&f9crew.Roster{Name: "Pilots"}), IsNil)
+	c.Assert(f9missioncontrol.AddRoster(&f9crew.Roster{Name: "Engineers"}), IsNil)
+
+	names := f9missioncontrol.ListRosters()
+	sort.Strings(names)
+
+	c.Check(names, DeepEquals, []string{"Engineers", "Pilots"})
+}
+
+func (*TestSuite) TestRemoveRoster(c *C) {
+	// clean up the registry
+	defer tearDownRosters(c)
+
+	roster := &f9crew.Roster{Name: "Pilots"}
+
+	c.Assert(f9missioncontrol.AddRoster(roster), IsNil)
+
+	c.Check(f9missioncontrol.RemoveRoster("Engineers"), IsNil)
+	c.Check(f9missioncontrol.RemoveRoster("Pilots"), DeepEquals, roster)
+	c.Check(len(f9missioncontrol.ListRosters()), Equals, 0)
+}
@@ -0,0 +1,324 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+)
+
+// ErrScheduleNotFound is the error returned when a schedule with the given
+// name hasn't been added to the Scheduler.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// Schedule is a recurring mission. Each time the schedule runs, the mission
+// with the ID in Params is created and added to the registry if it doesn't
+// exist. If it does exist and has crew, its Go/No-Go is initiated again, as
+// long as its upstream missions (see AddDependency) have all finished.
+type Schedule struct {
+	// Name is the unique name of the schedule.
+	Name string
+
+	// Spec is the cron-like spec for when the schedule runs. See CronSpec
+	// for the format.
+	Spec string
+
+	// Location is the time zone the Spec is in. If nil, it's UTC.
+	Location *time.Location
+
+	// Params are the parameters of the mission. If the Clock isn't set, the
+	// Scheduler's clock is used.
+	Params f9mission.MissionParams
+
+	// Roster is the name of a roster in the registry, added using
+	// AddRoster(), which is used as the mission's roster. It's looked up each
+	// time the mission is created.
+	Roster string
+
+	// Owner is the HashedKey of the crew member who owns the missions the
+	// schedule creates.
+	Owner string
+}
+
+// ScheduleStatus is the status of a schedule in a Scheduler.
+type ScheduleStatus struct {
+	Name   string
+	Spec   string
+	Paused bool
+
+	// Next is when the schedule will next run. It's the zero time if the
+	// schedule is paused.
+	Next time.Time
+
+	// LastRun is when the schedule last ran, and LastErr is the error from
+	// that run, if any.
+	LastRun time.Time
+	LastErr error
+}
+
+type scheduleEntry struct {
+	schedule Schedule
+	cron     *CronSpec
+	timer    f9mission.Timer
+	status   ScheduleStatus
+
+	// gen is incremented each time the timer is set, so that a run that was
+	// in progress when the schedule was paused and resumed doesn't set it
+	// again.
+	gen uint64
+}
+
+// Scheduler runs Schedules, creating or re-initiating their missions in the
+// registry each time they're due.
+type Scheduler struct {
+	clock     f9mission.Clock
+	schedules map[string]*scheduleEntry
+	mu        sync.Mutex
+}
+
+// NewScheduler returns a new Scheduler using the clock. If the clock is nil,
+// f9mission.SystemClock is used.
+func NewScheduler(clock f9mission.Clock) *Scheduler {
+	if clock == nil {
+		clock = f9mission.SystemClock
+	}
+
+	return &Scheduler{
+		clock:     clock,
+		schedules: make(map[string]*scheduleEntry),
+	}
+}
+
+// Add adds a schedule to the Scheduler, and starts it. This returns an error
+// if the schedule has no name, its spec is invalid, or a schedule with the
+// same name has already been added.
+func (s *Scheduler) Add(schedule *Schedule) error {
+	if schedule == nil || schedule.Name == "" {
+		return errors.New("the schedule must have a name")
+	}
+
+	cron, err := ParseCron(schedule.Spec)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[schedule.Name]; ok {
+		return fmt.Errorf("Schedule with name %q already added", schedule.Name)
+	}
+
+	e := &scheduleEntry{
+		schedule: *schedule,
+		cron:     cron,
+		status:   ScheduleStatus{Name: schedule.Name, Spec: schedule.Spec},
+	}
+
+	if e.schedule.Location == nil {
+		e.schedule.Location = time.UTC
+	}
+
+	s.schedules[schedule.Name] = e
+	s.start(e)
+
+	return nil
+}
+
+// start sets the timer for the next run of the schedule, replacing any
+// previous one. The mu must be held by the caller.
+func (s *Scheduler) start(e *scheduleEntry) {
+	e.stop()
+	e.gen++
+
+	now := s.clock.Now()
+	next := e.cron.Next(now.In(e.schedule.Location))
+
+	e.status.Next = next
+
+	if next.IsZero() {
+		return
+	}
+
+	gen := e.gen
+
+	e.timer = s.clock.AfterFunc(next.Sub(now), func() { s.fire(e, gen) })
+}
+
+func (s *Scheduler) fire(e *scheduleEntry, gen uint64) {
+	s.mu.Lock()
+
+	// the schedule was paused, resumed, or deleted after the timer fired
+	if s.schedules[e.schedule.Name] != e || e.status.Paused || e.gen != gen {
+		s.mu.Unlock()
+		return
+	}
+
+	schedule := e.schedule
+	e.status.LastRun = s.clock.Now()
+
+	s.mu.Unlock()
+
+	err := s.run(&schedule)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.status.LastErr = err
+
+	if s.schedules[e.schedule.Name] == e && !e.status.Paused && e.gen == gen {
+		s.start(e)
+	}
+}
+
+// run creates the schedule's mission, or initiates it again if it exists.
+func (s *Scheduler) run(schedule *Schedule) error {
+	if mc := GetMission(schedule.Params.ID); mc != nil {
+		if len(mc.Mission.Crew()) == 0 {
+			return nil
+		}
+
+		return initiateMission(schedule.Params.ID, mc)
+	}
+
+	params := schedule.Params
+
+	if params.Clock == nil {
+		params.Clock = s.clock
+	}
+
+	if schedule.Roster != "" {
+		if params.Roster = GetRoster(schedule.Roster); params.Roster == nil {
+			return fmt.Errorf("Roster with name %q is not registered", schedule.Roster)
+		}
+	}
+
+	mission, err := f9mission.NewMission(&params)
+
+	if err != nil {
+		return err
+	}
+
+	mc := &MissionControl{Mission: mission, Owner: schedule.Owner}
+
+	if err := AddMission(params.ID, mc); err != nil {
+		mission.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the status of the schedule with the given name. If it doesn't
+// exist this will return a ErrScheduleNotFound error.
+func (s *Scheduler) Get(name string) (ScheduleStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.schedules[name]
+
+	if !ok {
+		return ScheduleStatus{}, ErrScheduleNotFound
+	}
+
+	return e.status, nil
+}
+
+// List returns the status of every schedule, sorted by name.
+func (s *Scheduler) List() []ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]ScheduleStatus, 0, len(s.schedules))
+
+	for _, e := range s.schedules {
+		list = append(list, e.status)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// Pause stops the schedule from running until it's resumed. Pausing a paused
+// schedule does nothing. If it doesn't exist this will return a
+// ErrScheduleNotFound error.
+func (s *Scheduler) Pause(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.schedules[name]
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	e.stop()
+	e.status.Paused = true
+
+	return nil
+}
+
+// Resume restarts a paused schedule. Runs that were missed while it was paused
+// are skipped. Resuming a schedule that isn't paused does nothing. If it
+// doesn't exist this will return a ErrScheduleNotFound error.
+func (s *Scheduler) Resume(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.schedules[name]
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	if e.status.Paused {
+		e.status.Paused = false
+		s.start(e)
+	}
+
+	return nil
+}
+
+// Delete removes the schedule from the Scheduler. Missions it has already
+// created are not affected. If it doesn't exist this will return a
+// ErrScheduleNotFound error.
+func (s *Scheduler) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.schedules[name]
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	e.stop()
+	delete(s.schedules, name)
+
+	return nil
+}
+
+// Stop stops all of the schedules, and removes them from the Scheduler.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, e := range s.schedules {
+		e.stop()
+		delete(s.schedules, name)
+	}
+}
+
+func (e *scheduleEntry) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+
+	e.status.Next = time.Time{}
+}
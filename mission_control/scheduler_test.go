@@ -0,0 +1,194 @@
+package f9missioncontrol_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+// a Friday
+var scheduleEpoch = time.Date(2016, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+func (*TestSuite) TestScheduler_Add(c *C) {
+	s := f9missioncontrol.NewScheduler(f9missiontest.NewClock(scheduleEpoch))
+	defer s.Stop()
+
+	c.Check(s.Add(nil), ErrorMatches, "the schedule must have a name")
+	c.Check(s.Add(&f9missioncontrol.Schedule{Name: "standup", Spec: "bogus"}), NotNil)
+
+	c.Assert(s.Add(&f9missioncontrol.Schedule{Name: "standup", Spec: "0 9 * * 1-5"}), IsNil)
+	c.Check(s.Add(&f9missioncontrol.Schedule{Name: "standup", Spec: "0 9 * * 1-5"}), ErrorMatches, `Schedule with name "standup" already added`)
+
+	status, err := s.Get("standup")
+	c.Assert(err, IsNil)
+	c.Check(status.Spec, Equals, "0 9 * * 1-5")
+	c.Check(status.Next, Equals, scheduleEpoch.Add(time.Hour))
+
+	_, err = s.Get("bogus")
+	c.Check(err, Equals, f9missioncontrol.ErrScheduleNotFound)
+}
+
+func (*TestSuite) TestScheduler_Run(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+	defer tearDownRosters(c)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(f9missioncontrol.AddRoster(&f9crew.Roster{Name: "pilots", Crew: f9crew.Manifest{jeb}}), IsNil)
+
+	clock := f9missiontest.NewClock(scheduleEpoch)
+	s := f9missioncontrol.NewScheduler(clock)
+	defer s.Stop()
+
+	id := randUint32()
+
+	c.Assert(s.Add(&f9missioncontrol.Schedule{
+		Name:   "standup",
+		Spec:   "0 9 * * 1-5",
+		Params: f9mission.MissionParams{ID: id, Name: "Standup", GoNoGo: f9mission.GNGAll},
+		Roster: "pilots",
+		Owner:  "owner",
+	}), IsNil)
+
+	//
+	// Test that the mission is created on the first run
+	//
+	clock.Advance(time.Hour)
+
+	mc := f9missioncontrol.GetMission(id)
+	c.Assert(mc, NotNil)
+	c.Check(mc.Owner, Equals, "owner")
+	c.Check(mc.Mission.Name(), Equals, "Standup")
+	c.Check(mc.Mission.(*f9mission.Mission).Roster().Name, Equals, "pilots")
+	c.Check(mc.Mission.CurrentState(), Equals, f9mission.StateReady)
+
+	status, err := s.Get("standup")
+	c.Assert(err, IsNil)
+	c.Check(status.LastRun, Equals, scheduleEpoch.Add(time.Hour))
+	c.Check(status.LastErr, IsNil)
+
+	// the next run skips the weekend
+	c.Check(status.Next, Equals, time.Date(2016, time.January, 4, 9, 0, 0, 0, time.UTC))
+
+	//
+	// Test that the mission is initiated on the next run, once it has crew
+	//
+	c.Assert(mc.Mission.AddCrew(jeb, false), IsNil)
+
+	clock.Advance(time.Hour * 72)
+	c.Check(mc.Mission.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(f9missioncontrol.GetMission(id), Equals, mc)
+
+	//
+	// Test that errors are recorded
+	//
+	clock.Advance(time.Hour * 24)
+
+	status, err = s.Get("standup")
+	c.Assert(err, IsNil)
+	c.Check(status.LastErr, Equals, f9mission.ErrMissionInProgress)
+}
+
+func (*TestSuite) TestScheduler_PauseResumeDelete(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(scheduleEpoch)
+	s := f9missioncontrol.NewScheduler(clock)
+	defer s.Stop()
+
+	id := randUint32()
+
+	c.Assert(s.Add(&f9missioncontrol.Schedule{Name: "b", Spec: "@hourly", Params: f9mission.MissionParams{ID: id}}), IsNil)
+	c.Assert(s.Add(&f9missioncontrol.Schedule{Name: "a", Spec: "@daily", Params: f9mission.MissionParams{ID: id + 1}}), IsNil)
+
+	list := s.List()
+	c.Assert(len(list), Equals, 2)
+	c.Check(list[0].Name, Equals, "a")
+	c.Check(list[1].Name, Equals, "b")
+
+	//
+	// Test that paused schedules don't run
+	//
+	c.Assert(s.Pause("b"), IsNil)
+	c.Check(s.Pause("bogus"), Equals, f9missioncontrol.ErrScheduleNotFound)
+
+	status, _ := s.Get("b")
+	c.Check(status.Paused, Equals, true)
+	c.Check(status.Next.IsZero(), Equals, true)
+
+	clock.Advance(time.Hour)
+	c.Check(f9missioncontrol.GetMission(id), IsNil)
+
+	c.Assert(s.Resume("b"), IsNil)
+	c.Check(s.Resume("bogus"), Equals, f9missioncontrol.ErrScheduleNotFound)
+
+	status, _ = s.Get("b")
+	c.Check(status.Paused, Equals, false)
+	c.Check(status.Next, Equals, scheduleEpoch.Add(time.Hour*2))
+
+	clock.Advance(time.Hour)
+	c.Check(f9missioncontrol.GetMission(id), NotNil)
+
+	//
+	// Test that deleted schedules don't run
+	//
+	c.Assert(s.Delete("a"), IsNil)
+	c.Check(s.Delete("a"), Equals, f9missioncontrol.ErrScheduleNotFound)
+	c.Check(len(s.List()), Equals, 1)
+
+	clock.Advance(time.Hour * 24)
+	c.Check(f9missioncontrol.GetMission(id+1), IsNil)
+}
+
+func (*TestSuite) TestScheduler_ResumeDuringRun(c *C) {
+	// clean up the registry
+	defer tearDownRegistry(c)
+
+	clock := f9missiontest.NewClock(scheduleEpoch)
+	s := f9missioncontrol.NewScheduler(clock)
+	defer s.Stop()
+
+	id := randUint32()
+
+	// pause and resume the schedule while the run is initiating the mission
+	onStateChange := func(change f9mission.StateChange) {
+		if change.To == f9mission.StateVoting {
+			c.Check(s.Pause("standup"), IsNil)
+			c.Check(s.Resume("standup"), IsNil)
+		}
+	}
+
+	c.Assert(s.Add(&f9missioncontrol.Schedule{
+		Name:   "standup",
+		Spec:   "@hourly",
+		Params: f9mission.MissionParams{ID: id, OnStateChange: onStateChange},
+	}), IsNil)
+
+	clock.Advance(time.Hour)
+
+	mc := f9missioncontrol.GetMission(id)
+	c.Assert(mc, NotNil)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+	c.Assert(mc.Mission.AddCrew(jeb, false), IsNil)
+
+	//
+	// Test that the run doesn't set the timer again once it was resumed
+	//
+	clock.Advance(time.Hour)
+	c.Check(mc.Mission.CurrentState(), Equals, f9mission.StateVoting)
+	c.Check(clock.Timers(), Equals, 1)
+
+	status, err := s.Get("standup")
+	c.Assert(err, IsNil)
+	c.Check(status.Next, Equals, scheduleEpoch.Add(time.Hour*3))
+}
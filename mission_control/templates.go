@@ -0,0 +1,319 @@
+package f9missioncontrol
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/theckman/falcon9/mission"
+)
+
+// ErrTemplateNotFound is the error returned when creating a mission from a
+// template that hasn't been added to the registry.
+var ErrTemplateNotFound = errors.New("mission template not found")
+
+// MissionTemplate is a named set of mission settings, which missions can be
+// created from using NewMissionFromTemplate() and CreateMission().
+type MissionTemplate struct {
+	// Name is the unique name of the template.
+	Name string
+
+	// GoNoGo is the Go/No-Go setting of the missions.
+	GoNoGo f9mission.GNGSetting
+
+	// BlastoffingCooldown is the blastoff cooldown of the missions. If
+	// unset, the mission's default is used.
+	BlastoffingCooldown time.Duration
+
+	// Window is the launch window of the missions, relative to when each
+	// one is created. If nil, the missions don't have a launch window.
+	Window *TemplateWindow
+
+	// VoteLock is whether crew can change their votes once they're cast,
+	// including whether retracting a "Go" vote aborts the mission.
+	VoteLock f9mission.VoteLock
+
+	// Roster is the name of a roster in the registry, added using
+	// AddRoster(), which is used as the missions' roster. It's looked up
+	// each time a mission is created.
+	Roster string
+
+	// Roles is the role of each crew member within the missions, keyed by
+	// their HashedKey.
+	Roles map[string]string
+}
+
+// TemplateWindow is a launch window relative to when a mission is created
+// from a template. See f9mission.LaunchWindow for what each time means.
+type TemplateWindow struct {
+	VotingOpens time.Duration
+	Opens       time.Duration
+	Closes      time.Duration
+}
+
+// TemplateOverrides are the settings of a mission that differ from those of
+// the template it's created from. Fields that are nil, or zero, use the
+// template's setting.
+type TemplateOverrides struct {
+	GoNoGo              *f9mission.GNGSetting
+	BlastoffingCooldown time.Duration
+	Window              *TemplateWindow
+	VoteLock            *f9mission.VoteLock
+	Roster              string
+
+	// Roles are added to the template's roles, replacing the template's role
+	// for the same crew member.
+	Roles map[string]string
+}
+
+func validateTemplateWindow(w *TemplateWindow) error {
+	if w == nil {
+		return nil
+	}
+
+	if w.VotingOpens < 0 || w.VotingOpens > w.Opens {
+		return errors.New("voting for the launch window must open before the window does")
+	}
+
+	if w.Closes <= w.Opens {
+		return errors.New("the launch window must close after it opens")
+	}
+
+	return nil
+}
+
+func validateGNGSetting(gng f9mission.GNGSetting) error {
+	if gng > f9mission.GNGWeightedQuorum {
+		return errors.New("unknown Go/No-Go setting")
+	}
+
+	return nil
+}
+
+func validateVoteLock(lock f9mission.VoteLock) error {
+	if lock > f9mission.VoteLockAbortOnRetract {
+		return errors.New("unknown vote lock setting")
+	}
+
+	return nil
+}
+
+// copy returns a copy of the template, which doesn't share its roles.
+func (t *MissionTemplate) copy() *MissionTemplate {
+	c := *t
+
+	if t.Window != nil {
+		w := *t.Window
+		c.Window = &w
+	}
+
+	if t.Roles != nil {
+		c.Roles = make(map[string]string, len(t.Roles))
+
+		for hashedKey, role := range t.Roles {
+			c.Roles[hashedKey] = role
+		}
+	}
+
+	return &c
+}
+
+// AddTemplate adds a mission template to the registry. This will only return
+// an error when the template has no name, an unknown GoNoGo or VoteLock
+// setting, or an invalid launch window, or the registry already has a
+// template with that name.
+func AddTemplate(template *MissionTemplate) error {
+	if template == nil || template.Name == "" {
+		return errors.New("the mission template must have a name")
+	}
+
+	if err := validateGNGSetting(template.GoNoGo); err != nil {
+		return err
+	}
+
+	if err := validateVoteLock(template.VoteLock); err != nil {
+		return err
+	}
+
+	if err := validateTemplateWindow(template.Window); err != nil {
+		return err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry.templates[template.Name]; ok {
+		return fmt.Errorf("Mission template with name %q already registered", template.Name)
+	}
+
+	registry.templates[template.Name] = template.copy()
+
+	return nil
+}
+
+// GetTemplate returns a copy of a mission template, based on its name, if one
+// has been added. If the template doesn't exist this just returns nil.
+func GetTemplate(name string) *MissionTemplate {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if template, ok := registry.templates[name]; ok {
+		return template.copy()
+	}
+
+	return nil
+}
+
+// RemoveTemplate purges a mission template from the registry. Missions
+// created from the template are not affected. If the template existed this
+// will return the template, otherwise it will return nil.
+func RemoveTemplate(name string) *MissionTemplate {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if template, ok := registry.templates[name]; ok {
+		delete(registry.templates, name)
+		return template
+	}
+
+	return nil
+}
+
+// ListTemplates returns a slice of the mission template names. They are in no
+// particular order.
+func ListTemplates() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	slice := make([]string, 0, len(registry.templates))
+
+	for name := range registry.templates {
+		slice = append(slice, name)
+	}
+
+	return slice
+}
+
+// apply applies the overrides to the template.
+func (t *MissionTemplate) apply(o *TemplateOverrides) error {
+	if o == nil {
+		return nil
+	}
+
+	if o.GoNoGo != nil {
+		if err := validateGNGSetting(*o.GoNoGo); err != nil {
+			return err
+		}
+	}
+
+	if o.VoteLock != nil {
+		if err := validateVoteLock(*o.VoteLock); err != nil {
+			return err
+		}
+	}
+
+	if err := validateTemplateWindow(o.Window); err != nil {
+		return err
+	}
+
+	if o.GoNoGo != nil {
+		t.GoNoGo = *o.GoNoGo
+	}
+
+	if o.BlastoffingCooldown != 0 {
+		t.BlastoffingCooldown = o.BlastoffingCooldown
+	}
+
+	if o.Window != nil {
+		t.Window = o.Window
+	}
+
+	if o.VoteLock != nil {
+		t.VoteLock = *o.VoteLock
+	}
+
+	if o.Roster != "" {
+		t.Roster = o.Roster
+	}
+
+	if len(o.Roles) > 0 && t.Roles == nil {
+		t.Roles = make(map[string]string, len(o.Roles))
+	}
+
+	for hashedKey, role := range o.Roles {
+		t.Roles[hashedKey] = role
+	}
+
+	return nil
+}
+
+// NewMissionFromTemplate creates a mission from the template with the name,
+// with the overrides applied. The params are the rest of the mission's
+// parameters, such as its ID, Name, and Clock; the settings from the template
+// replace those in the params. The overrides may be nil.
+//
+// If the template doesn't exist this will return a ErrTemplateNotFound error.
+func NewMissionFromTemplate(name string, params f9mission.MissionParams, overrides *TemplateOverrides) (*f9mission.Mission, error) {
+	template := GetTemplate(name)
+
+	if template == nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	if err := template.apply(overrides); err != nil {
+		return nil, err
+	}
+
+	params.GoNoGo = template.GoNoGo
+	params.BlastoffingCooldown = template.BlastoffingCooldown
+	params.VoteLock = template.VoteLock
+	params.Roles = template.Roles
+	params.Roster = nil
+	params.Window = nil
+
+	if template.Roster != "" {
+		if params.Roster = GetRoster(template.Roster); params.Roster == nil {
+			return nil, fmt.Errorf("Roster with name %q is not registered", template.Roster)
+		}
+	}
+
+	if w := template.Window; w != nil {
+		clock := params.Clock
+
+		if clock == nil {
+			clock = f9mission.SystemClock
+		}
+
+		now := clock.Now()
+
+		params.Window = &f9mission.LaunchWindow{
+			VotingOpens: now.Add(w.VotingOpens),
+			Opens:       now.Add(w.Opens),
+			Closes:      now.Add(w.Closes),
+		}
+	}
+
+	return f9mission.NewMission(&params)
+}
+
+// CreateMission creates a mission from the template with the name, in the
+// same way as NewMissionFromTemplate(), and adds it to the registry with the
+// owner. The Template of the MissionControl is set to the name. This returns
+// an error if the mission can't be created, or the registry already has a
+// mission with its ID.
+func CreateMission(name string, params f9mission.MissionParams, overrides *TemplateOverrides, owner string) (*MissionControl, error) {
+	mission, err := NewMissionFromTemplate(name, params, overrides)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mc := &MissionControl{Mission: mission, Owner: owner, Template: name}
+
+	if err := AddMission(params.ID, mc); err != nil {
+		mission.Close()
+		return nil, err
+	}
+
+	return mc, nil
+}
@@ -0,0 +1,216 @@
+package f9missioncontrol_test
+
+import (
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission"
+	"github.com/theckman/falcon9/mission/missiontest"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+func tearDownTemplates(c *C) {
+	for _, name := range f9missioncontrol.ListTemplates() {
+		c.Check(f9missioncontrol.RemoveTemplate(name), NotNil)
+	}
+}
+
+func (*TestSuite) TestAddTemplate(c *C) {
+	// clean up the registry
+	defer tearDownTemplates(c)
+
+	c.Check(f9missioncontrol.AddTemplate(nil), ErrorMatches, "the mission template must have a name")
+	c.Check(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{}), ErrorMatches, "the mission template must have a name")
+
+	c.Check(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{
+		Name:   "deploy",
+		Window: &f9missioncontrol.TemplateWindow{Opens: time.Hour, Closes: time.Hour},
+	}), ErrorMatches, "the launch window must close after it opens")
+
+	c.Check(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{
+		Name:   "deploy",
+		Window: &f9missioncontrol.TemplateWindow{VotingOpens: time.Hour * 2, Opens: time.Hour, Closes: time.Hour * 3},
+	}), ErrorMatches, "voting for the launch window must open before the window does")
+
+	c.Check(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{
+		Name:   "deploy",
+		GoNoGo: f9mission.GNGWeightedQuorum + 1,
+	}), ErrorMatches, "unknown Go/No-Go setting")
+
+	c.Check(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{
+		Name:     "deploy",
+		VoteLock: f9mission.VoteLockAbortOnRetract + 1,
+	}), ErrorMatches, "unknown vote lock setting")
+
+	template := &f9missioncontrol.MissionTemplate{
+		Name:   "deploy",
+		GoNoGo: f9mission.GNGQuorum,
+		Roles:  map[string]string{"0": "flight director"},
+	}
+
+	c.Assert(f9missioncontrol.AddTemplate(template), IsNil)
+
+	//
+	// Test that the registry keeps its own copy of the template
+	//
+	template.Roles["0"] = "capcom"
+
+	got := f9missioncontrol.GetTemplate("deploy")
+	c.Assert(got, NotNil)
+	c.Check(got.GoNoGo, Equals, f9mission.GNGQuorum)
+	c.Check(got.Roles, DeepEquals, map[string]string{"0": "flight director"})
+
+	got.Roles["0"] = "capcom"
+	c.Check(f9missioncontrol.GetTemplate("deploy").Roles["0"], Equals, "flight director")
+
+	c.Check(f9missioncontrol.GetTemplate("rollback"), IsNil)
+	c.Check(f9missioncontrol.ListTemplates(), DeepEquals, []string{"deploy"})
+
+	//
+	// Test that you can't register it twice
+	//
+	c.Check(f9missioncontrol.AddTemplate(template), ErrorMatches, `Mission template with name "deploy" already registered`)
+
+	c.Check(f9missioncontrol.RemoveTemplate("deploy"), NotNil)
+	c.Check(f9missioncontrol.RemoveTemplate("deploy"), IsNil)
+}
+
+func (*TestSuite) TestNewMissionFromTemplate(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownTemplates(c)
+	defer tearDownRosters(c)
+
+	jeb, err := f9crew.NewCrewMember("Jebediah Kerman", "0")
+	c.Assert(err, IsNil)
+
+	c.Assert(f9missioncontrol.AddRoster(&f9crew.Roster{Name: "Pilots", Crew: f9crew.Manifest{jeb}}), IsNil)
+
+	c.Assert(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{
+		Name:                "deploy",
+		GoNoGo:              f9mission.GNGQuorum,
+		BlastoffingCooldown: time.Minute,
+		Window:              &f9missioncontrol.TemplateWindow{VotingOpens: time.Minute, Opens: time.Hour, Closes: time.Hour * 2},
+		VoteLock:            f9mission.VoteLockOnCast,
+		Roster:              "Pilots",
+		Roles:               map[string]string{jeb.HashedKey(): "flight director"},
+	}), IsNil)
+
+	clock := f9missiontest.NewClock(time.Now())
+
+	_, err = f9missioncontrol.NewMissionFromTemplate("rollback", f9mission.MissionParams{}, nil)
+	c.Check(err, Equals, f9missioncontrol.ErrTemplateNotFound)
+
+	//
+	// Test that the mission has the template's settings
+	//
+	m, err := f9missioncontrol.NewMissionFromTemplate("deploy", f9mission.MissionParams{
+		ID:     42,
+		Name:   "Mun landing",
+		GoNoGo: f9mission.GNGWeightedQuorum,
+		Clock:  clock,
+	}, nil)
+	c.Assert(err, IsNil)
+	c.Check(m.ID(), Equals, uint32(42))
+	c.Check(m.Name(), Equals, "Mun landing")
+	c.Check(m.GNGSetting(), Equals, f9mission.GNGQuorum)
+	c.Check(m.Roster().Name, Equals, "Pilots")
+	c.Check(m.LaunchWindow(), DeepEquals, &f9mission.LaunchWindow{
+		VotingOpens: clock.Now().Add(time.Minute),
+		Opens:       clock.Now().Add(time.Hour),
+		Closes:      clock.Now().Add(time.Hour * 2),
+	})
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Check(m.CrewStatus()[jeb.HashedKey()].Role, Equals, "flight director")
+
+	clock.Advance(time.Hour)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+	c.Check(m.CurrentState(), Equals, f9mission.StateBlastoffing)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteNo)
+	c.Check(err, Equals, f9mission.ErrVoteLocked)
+
+	clock.Advance(time.Minute)
+	c.Check(m.CurrentState(), Equals, f9mission.StateFinished)
+
+	//
+	// Test that the overrides replace the template's settings
+	//
+	gng, lock := f9mission.GNGAll, f9mission.VoteLockNone
+
+	m, err = f9missioncontrol.NewMissionFromTemplate("deploy", f9mission.MissionParams{Clock: clock}, &f9missioncontrol.TemplateOverrides{
+		GoNoGo:   &gng,
+		Window:   &f9missioncontrol.TemplateWindow{Opens: time.Minute, Closes: time.Minute * 2},
+		VoteLock: &lock,
+		Roles:    map[string]string{"1": "capcom"},
+	})
+	c.Assert(err, IsNil)
+	c.Check(m.GNGSetting(), Equals, f9mission.GNGAll)
+	c.Check(m.LaunchWindow().Opens, Equals, clock.Now().Add(time.Minute))
+
+	c.Assert(m.AddCrew(jeb, false), IsNil)
+	c.Check(m.CrewStatus()[jeb.HashedKey()].Role, Equals, "flight director")
+
+	clock.Advance(time.Minute)
+	c.Assert(m.Initiate(), IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteYes)
+	c.Assert(err, IsNil)
+
+	_, err = m.UpdateVote(jeb.HashedKey(), f9mission.VoteNo)
+	c.Check(err, IsNil)
+
+	// the template itself is unchanged
+	c.Check(f9missioncontrol.GetTemplate("deploy").Roles, DeepEquals, map[string]string{jeb.HashedKey(): "flight director"})
+
+	_, err = f9missioncontrol.NewMissionFromTemplate("deploy", f9mission.MissionParams{}, &f9missioncontrol.TemplateOverrides{Roster: "Engineers"})
+	c.Check(err, ErrorMatches, `Roster with name "Engineers" is not registered`)
+
+	//
+	// Test that overrides with unknown settings are rejected
+	//
+	gng = f9mission.GNGWeightedQuorum + 1
+
+	_, err = f9missioncontrol.NewMissionFromTemplate("deploy", f9mission.MissionParams{}, &f9missioncontrol.TemplateOverrides{GoNoGo: &gng})
+	c.Check(err, ErrorMatches, "unknown Go/No-Go setting")
+
+	lock = f9mission.VoteLockAbortOnRetract + 1
+
+	_, err = f9missioncontrol.NewMissionFromTemplate("deploy", f9mission.MissionParams{}, &f9missioncontrol.TemplateOverrides{VoteLock: &lock})
+	c.Check(err, ErrorMatches, "unknown vote lock setting")
+}
+
+func (*TestSuite) TestCreateMission(c *C) {
+	var err error
+
+	// clean up the registry
+	defer tearDownRegistry(c)
+	defer tearDownTemplates(c)
+
+	c.Assert(f9missioncontrol.AddTemplate(&f9missioncontrol.MissionTemplate{Name: "deploy"}), IsNil)
+
+	id := randUint32()
+
+	mc, err := f9missioncontrol.CreateMission("deploy", f9mission.MissionParams{ID: id}, nil, testOwner)
+	c.Assert(err, IsNil)
+	c.Check(mc.Owner, Equals, testOwner)
+	c.Check(mc.Template, Equals, "deploy")
+	c.Check(f9missioncontrol.GetMission(id), Equals, mc)
+
+	//
+	// Test that the mission isn't created when the ID is taken
+	//
+	_, err = f9missioncontrol.CreateMission("deploy", f9mission.MissionParams{ID: id}, nil, testOwner)
+	c.Check(err, ErrorMatches, "Mission with ID [0-9]+ already registered")
+
+	_, err = f9missioncontrol.CreateMission("rollback", f9mission.MissionParams{ID: id + 1}, nil, testOwner)
+	c.Check(err, Equals, f9missioncontrol.ErrTemplateNotFound)
+	c.Check(f9missioncontrol.GetMission(id+1), IsNil)
+}
@@ -0,0 +1,135 @@
+package f9missioncontrol
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/theckman/falcon9/crew"
+)
+
+// ErrNoClientCertificate is the error returned from CrewFromConn() if the
+// client did not present a certificate during the TLS handshake.
+var ErrNoClientCertificate = errors.New("the client did not present a certificate")
+
+// ErrClientCertNotVerified is the error returned from CrewFromConn() if the
+// client presented a certificate, but it wasn't verified against the client
+// CAs during the TLS handshake.
+var ErrClientCertNotVerified = errors.New("the client certificate was not verified")
+
+// TLSConfig is the TLS configuration for a MissionControl listener. The
+// certificate and key files are PEM encoded.
+type TLSConfig struct {
+	// CertFile is the path to the server's certificate.
+	CertFile string
+
+	// KeyFile is the path to the server certificate's private key.
+	KeyFile string
+
+	// ClientCAFile is the path to the CA certificates used to verify client
+	// certificates. If this is set client certificates are verified when
+	// presented, otherwise client certificates are not requested.
+	ClientCAFile string
+
+	// RequireClientCert makes a verified client certificate mandatory
+	// (mutual TLS). This requires ClientCAFile to be set.
+	RequireClientCert bool
+}
+
+// Config loads the certificates and returns the *tls.Config to use for a
+// listener.
+func (tc *TLSConfig) Config() (*tls.Config, error) {
+	if tc.CertFile == "" || tc.KeyFile == "" {
+		return nil, errors.New("the TLS certificate and key files must both be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %s", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if tc.ClientCAFile == "" {
+		if tc.RequireClientCert {
+			return nil, errors.New("requiring client certificates needs a client CA file")
+		}
+
+		return config, nil
+	}
+
+	pem, err := ioutil.ReadFile(tc.ClientCAFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", tc.ClientCAFile)
+	}
+
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+
+	if tc.RequireClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+// ListenTLS is a function to announce on the local network address using TLS.
+// The network and address parameters are the same as those of net.Listen().
+func ListenTLS(network, address string, tc *TLSConfig) (net.Listener, error) {
+	if tc == nil {
+		return nil, errors.New("TLS config cannot be nil")
+	}
+
+	config, err := tc.Config()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tls.Listen(network, address, config)
+}
+
+// CrewFromConn returns the crew member identified by the client certificate
+// presented on the connection. This completes the TLS handshake if it hasn't
+// been done yet. The certificate must have been verified against the client
+// CAs, so a listener that accepts certificates without verifying them will
+// get a ErrClientCertNotVerified error.
+//
+// The crew member's name is the certificate's Common Name, and their key is
+// the certificate's public key, which is hashed using hasher. This means the
+// crew member's HashedKey stays the same when their certificate is renewed
+// with the same key pair.
+func CrewFromConn(conn *tls.Conn, hasher f9crew.Hasher) (*f9crew.CrewMember, error) {
+	if err := conn.Handshake(); err != nil {
+		return nil, err
+	}
+
+	state := conn.ConnectionState()
+
+	if len(state.PeerCertificates) == 0 {
+		return nil, ErrNoClientCertificate
+	}
+
+	// an unverified certificate could be self-signed by anyone
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, ErrClientCertNotVerified
+	}
+
+	cert := state.VerifiedChains[0][0]
+
+	return f9crew.NewCrewMemberWithHasher(cert.Subject.CommonName, string(cert.RawSubjectPublicKeyInfo), hasher)
+}
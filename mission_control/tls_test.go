@@ -0,0 +1,300 @@
+package f9missioncontrol_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/theckman/falcon9/crew"
+	"github.com/theckman/falcon9/mission_control"
+
+	. "gopkg.in/check.v1"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func (tc *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{tc.der}, PrivateKey: tc.key}
+}
+
+func (tc *testCert) writePEM(c *C, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	keyDER, err := x509.MarshalECPrivateKey(tc.key)
+	c.Assert(err, IsNil)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	c.Assert(ioutil.WriteFile(certFile, certPEM, 0600), IsNil)
+	c.Assert(ioutil.WriteFile(keyFile, keyPEM, 0600), IsNil)
+
+	return certFile, keyFile
+}
+
+// genCert generates a certificate signed by parent. If parent is nil the
+// certificate is a self-signed CA.
+func genCert(c *C, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	c.Assert(err, IsNil)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signerCert, signerKey := tmpl, key
+
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	c.Assert(err, IsNil)
+
+	cert, err := x509.ParseCertificate(der)
+	c.Assert(err, IsNil)
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+type acceptResult struct {
+	crew *f9crew.CrewMember
+	err  error
+}
+
+func acceptCrew(ln net.Listener, hasher f9crew.Hasher) <-chan acceptResult {
+	ch := make(chan acceptResult, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+
+		if err != nil {
+			ch <- acceptResult{err: err}
+			return
+		}
+
+		defer conn.Close()
+
+		crew, err := f9missioncontrol.CrewFromConn(conn.(*tls.Conn), hasher)
+		ch <- acceptResult{crew: crew, err: err}
+	}()
+
+	return ch
+}
+
+func (*TestSuite) TestTLSConfig_Config(c *C) {
+	var err error
+
+	dir := c.MkDir()
+	ca := genCert(c, "Test CA", nil)
+	server := genCert(c, "mission control", ca)
+
+	certFile, keyFile := server.writePEM(c, dir, "server")
+	caFile, _ := ca.writePEM(c, dir, "ca")
+
+	_, err = (&f9missioncontrol.TLSConfig{CertFile: certFile}).Config()
+	c.Check(err, ErrorMatches, "the TLS certificate and key files must both be set")
+
+	_, err = (&f9missioncontrol.TLSConfig{CertFile: certFile, KeyFile: filepath.Join(dir, "nope")}).Config()
+	c.Check(err, ErrorMatches, "failed to load TLS key pair: .*")
+
+	_, err = (&f9missioncontrol.TLSConfig{CertFile: certFile, KeyFile: keyFile, RequireClientCert: true}).Config()
+	c.Check(err, ErrorMatches, "requiring client certificates needs a client CA file")
+
+	_, err = (&f9missioncontrol.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}).Config()
+	c.Check(err, ErrorMatches, "no certificates found in client CA file .*")
+
+	config, err := (&f9missioncontrol.TLSConfig{CertFile: certFile, KeyFile: keyFile}).Config()
+	c.Assert(err, IsNil)
+	c.Check(config.ClientAuth, Equals, tls.NoClientCert)
+
+	config, err = (&f9missioncontrol.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}).Config()
+	c.Assert(err, IsNil)
+	c.Check(config.ClientAuth, Equals, tls.VerifyClientCertIfGiven)
+
+	config, err = (&f9missioncontrol.TLSConfig{
+		CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, RequireClientCert: true,
+	}).Config()
+	c.Assert(err, IsNil)
+	c.Check(config.ClientAuth, Equals, tls.RequireAndVerifyClientCert)
+}
+
+func (*TestSuite) TestListenTLS(c *C) {
+	dir := c.MkDir()
+	ca := genCert(c, "Test CA", nil)
+	server := genCert(c, "mission control", ca)
+	client := genCert(c, "Jebediah Kerman", ca)
+	rogue := genCert(c, "Bill Kerman", genCert(c, "Rogue CA", nil))
+
+	certFile, keyFile := server.writePEM(c, dir, "server")
+	caFile, _ := ca.writePEM(c, dir, "ca")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	ln, err := f9missioncontrol.ListenTLS("tcp", "127.0.0.1:0", &f9missioncontrol.TLSConfig{
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		ClientCAFile:      caFile,
+		RequireClientCert: true,
+	})
+	c.Assert(err, IsNil)
+	defer ln.Close()
+
+	//
+	// Test that the client certificate maps to a crew member
+	//
+	ch := acceptCrew(ln, f9crew.SHA256Hasher)
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{client.tlsCertificate()},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conn.Handshake(), IsNil)
+
+	res := <-ch
+	conn.Close()
+
+	c.Assert(res.err, IsNil)
+	c.Check(res.crew.Name(), Equals, "Jebediah Kerman")
+	c.Check(res.crew.HashedKey(), Equals, f9crew.HashKey(string(client.cert.RawSubjectPublicKeyInfo)))
+
+	//
+	// Test that the crew member's HashedKey is made using the hasher
+	//
+	hasher, err := f9crew.NewHMACHasher([]byte("secret"))
+	c.Assert(err, IsNil)
+
+	ch = acceptCrew(ln, hasher)
+
+	conn, err = tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{client.tlsCertificate()},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conn.Handshake(), IsNil)
+
+	res = <-ch
+	conn.Close()
+
+	c.Assert(res.err, IsNil)
+	c.Check(res.crew.HashedKey(), Equals, hasher.Hash(string(client.cert.RawSubjectPublicKeyInfo)))
+
+	//
+	// Test that a certificate from an unknown CA is rejected
+	//
+	ch = acceptCrew(ln, f9crew.SHA256Hasher)
+
+	conn, err = tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{rogue.tlsCertificate()},
+	})
+
+	if err == nil {
+		// TLS 1.3 clients learn of the rejection on their first read
+		_, err = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+
+	c.Check(err, NotNil)
+	c.Check((<-ch).err, NotNil)
+
+	//
+	// Test that ListenTLS requires a config
+	//
+	_, err = f9missioncontrol.ListenTLS("tcp", "127.0.0.1:0", nil)
+	c.Check(err, ErrorMatches, "TLS config cannot be nil")
+}
+
+func (*TestSuite) TestCrewFromConn(c *C) {
+	dir := c.MkDir()
+	ca := genCert(c, "Test CA", nil)
+	server := genCert(c, "mission control", ca)
+
+	certFile, keyFile := server.writePEM(c, dir, "server")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	ln, err := f9missioncontrol.ListenTLS("tcp", "127.0.0.1:0", &f9missioncontrol.TLSConfig{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	})
+	c.Assert(err, IsNil)
+	defer ln.Close()
+
+	ch := acceptCrew(ln, f9crew.SHA256Hasher)
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{RootCAs: roots})
+	c.Assert(err, IsNil)
+	c.Assert(conn.Handshake(), IsNil)
+
+	res := <-ch
+	conn.Close()
+
+	c.Check(res.err, Equals, f9missioncontrol.ErrNoClientCertificate)
+	c.Check(res.crew, IsNil)
+}
+
+func (*TestSuite) TestCrewFromConn_NotVerified(c *C) {
+	ca := genCert(c, "Test CA", nil)
+	server := genCert(c, "mission control", ca)
+	rogue := genCert(c, "Jebediah Kerman", nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{server.tlsCertificate()},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	c.Assert(err, IsNil)
+	defer ln.Close()
+
+	//
+	// Test that a self-signed certificate can't be used to claim a crew
+	// member's identity
+	//
+	ch := acceptCrew(ln, f9crew.SHA256Hasher)
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{rogue.tlsCertificate()},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conn.Handshake(), IsNil)
+
+	res := <-ch
+	conn.Close()
+
+	c.Check(res.err, Equals, f9missioncontrol.ErrClientCertNotVerified)
+	c.Check(res.crew, IsNil)
+}